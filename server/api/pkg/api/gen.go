@@ -10,10 +10,12 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
@@ -21,6 +23,65 @@ import (
 	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
 )
 
+const (
+	ApiKeyScopes = "apiKey.Scopes"
+)
+
+// Defines values for ApiKeyScope.
+const (
+	AllowlistRead  ApiKeyScope = "allowlist:read"
+	AllowlistWrite ApiKeyScope = "allowlist:write"
+	AuditRead      ApiKeyScope = "audit:read"
+	KeysAdmin      ApiKeyScope = "keys:admin"
+	NodesRead      ApiKeyScope = "nodes:read"
+	SourcesAdmin   ApiKeyScope = "sources:admin"
+	SourcesRead    ApiKeyScope = "sources:read"
+)
+
+// Defines values for AuditResourceType.
+const (
+	AuditResourceTypeAllowlist      AuditResourceType = "allowlist"
+	AuditResourceTypeAllowlistEntry AuditResourceType = "allowlist_entry"
+	AuditResourceTypeApiKey         AuditResourceType = "api_key"
+	AuditResourceTypeSource         AuditResourceType = "source"
+)
+
+// Defines values for IngestionRunStatus.
+const (
+	Failure   IngestionRunStatus = "failure"
+	Pending   IngestionRunStatus = "pending"
+	Running   IngestionRunStatus = "running"
+	Success   IngestionRunStatus = "success"
+	Unchanged IngestionRunStatus = "unchanged"
+)
+
+// Defines values for IngestionRunTrigger.
+const (
+	Manual    IngestionRunTrigger = "manual"
+	Scheduled IngestionRunTrigger = "scheduled"
+)
+
+// Defines values for NodeHistoryEntryEvent.
+const (
+	Appeared    NodeHistoryEntryEvent = "appeared"
+	Disappeared NodeHistoryEntryEvent = "disappeared"
+)
+
+// Defines values for SourceFormat.
+const (
+	SourceFormatCsv              SourceFormat = "csv"
+	SourceFormatJson             SourceFormat = "json"
+	SourceFormatLines            SourceFormat = "lines"
+	SourceFormatTorExitAddresses SourceFormat = "tor-exit-addresses"
+)
+
+// Defines values for ExportNodesParamsFormat.
+const (
+	ExportNodesParamsFormatCsv   ExportNodesParamsFormat = "csv"
+	ExportNodesParamsFormatJsonl ExportNodesParamsFormat = "jsonl"
+	ExportNodesParamsFormatTxt   ExportNodesParamsFormat = "txt"
+)
+
 // AddAllowlistEntryInput defines model for AddAllowlistEntryInput.
 type AddAllowlistEntryInput struct {
 	Cidr string `json:"cidr"`
@@ -39,22 +100,169 @@ type AllowlistEntryItem struct {
 	Id          int    `json:"id"`
 }
 
+// ApiKeyEntry defines model for ApiKeyEntry.
+type ApiKeyEntry struct {
+	CreatedAt  string  `json:"created_at"`
+	Id         int     `json:"id"`
+	LastUsedAt *string `json:"last_used_at,omitempty"`
+	Name       string  `json:"name"`
+
+	// Prefix The first characters of the key, used to tell keys apart
+	Prefix    string        `json:"prefix"`
+	RevokedAt *string       `json:"revoked_at,omitempty"`
+	Scopes    []ApiKeyScope `json:"scopes"`
+}
+
+// ApiKeyScope defines model for ApiKeyScope.
+type ApiKeyScope string
+
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	// Actor Name of the API key that made the change
+	Actor string `json:"actor"`
+
+	// ActorKeyId Id of the API key that made the change, missing once the key is deleted
+	ActorKeyId *int `json:"actor_key_id,omitempty"`
+
+	// After The resource after the change, missing when it was deleted
+	After *map[string]interface{} `json:"after,omitempty"`
+
+	// Before The resource before the change, missing when it was created
+	Before     *map[string]interface{} `json:"before,omitempty"`
+	Id         int                     `json:"id"`
+	OccurredAt string                  `json:"occurred_at"`
+
+	// Operation What was done, for example source.create or allowlist_entry.remove
+	Operation string `json:"operation"`
+
+	// RequestId The X-Request-Id of the request that made the change
+	RequestId    string            `json:"request_id"`
+	ResourceId   string            `json:"resource_id"`
+	ResourceType AuditResourceType `json:"resource_type"`
+}
+
+// AuditResourceType defines model for AuditResourceType.
+type AuditResourceType string
+
 // CreateAllowlistInput defines model for CreateAllowlistInput.
 type CreateAllowlistInput struct {
 	Name string `json:"name"`
 }
 
+// CreateApiKeyInput defines model for CreateApiKeyInput.
+type CreateApiKeyInput struct {
+	Name   string        `json:"name"`
+	Scopes []ApiKeyScope `json:"scopes"`
+}
+
 // CreateSourceEntryInput defines model for CreateSourceEntryInput.
 type CreateSourceEntryInput struct {
-	Name   string `json:"name"`
-	Period string `json:"period"`
-	Url    string `json:"url"`
+	// Credentials Sent with every request for the source url. Encrypted at rest and never returned, an empty object removes them
+	Credentials *SourceCredentials `json:"credentials,omitempty"`
+
+	// Format How the document served by the source url should be parsed. Defaults to csv
+	Format        *SourceFormat        `json:"format,omitempty"`
+	FormatOptions *SourceFormatOptions `json:"format_options,omitempty"`
+	Name          string               `json:"name"`
+	Period        string               `json:"period"`
+	Url           string               `json:"url"`
+}
+
+// CreatedApiKeyEntry defines model for CreatedApiKeyEntry.
+type CreatedApiKeyEntry struct {
+	CreatedAt string `json:"created_at"`
+	Id        int    `json:"id"`
+
+	// Key The API key to send in the X-Api-Key header, it can not be retrieved again
+	Key        string  `json:"key"`
+	LastUsedAt *string `json:"last_used_at,omitempty"`
+	Name       string  `json:"name"`
+
+	// Prefix The first characters of the key, used to tell keys apart
+	Prefix    string        `json:"prefix"`
+	RevokedAt *string       `json:"revoked_at,omitempty"`
+	Scopes    []ApiKeyScope `json:"scopes"`
+}
+
+// IngestionRunEntry defines model for IngestionRunEntry.
+type IngestionRunEntry struct {
+	// BytesFetched Bytes of feed read across every attempt
+	BytesFetched *int64 `json:"bytes_fetched,omitempty"`
+
+	// Error Why the ingestion failed
+	Error      *string `json:"error,omitempty"`
+	FinishedAt *string `json:"finished_at,omitempty"`
+
+	// HttpStatus Status of the last response from the feed, missing when no response was received
+	HttpStatus *int  `json:"http_status,omitempty"`
+	Id         int64 `json:"id"`
+
+	// NodesAdded Nodes that appeared in the published snapshot
+	NodesAdded *int `json:"nodes_added,omitempty"`
+
+	// NodesRemoved Nodes that disappeared from the published snapshot
+	NodesRemoved *int   `json:"nodes_removed,omitempty"`
+	RequestedAt  string `json:"requested_at"`
+
+	// RowsParsed Feed entries that parsed into an ip address
+	RowsParsed *int `json:"rows_parsed,omitempty"`
+
+	// RowsRejected Feed entries that were skipped because they did not hold a valid ip address
+	RowsRejected *int    `json:"rows_rejected,omitempty"`
+	SourceId     int     `json:"source_id"`
+	StartedAt    *string `json:"started_at,omitempty"`
+
+	// Status pending until an ingester picks the run up, running while it ingests the source, then the outcome
+	Status IngestionRunStatus `json:"status"`
+
+	// Trigger manual when the run was requested through the api, scheduled when the source's period came around
+	Trigger IngestionRunTrigger `json:"trigger"`
+
+	// Version The source version visible once the run finished, it moves forward when the run published a new snapshot
+	Version *int64 `json:"version,omitempty"`
 }
 
+// IngestionRunStatus pending until an ingester picks the run up, running while it ingests the source, then the outcome
+type IngestionRunStatus string
+
+// IngestionRunTrigger manual when the run was requested through the api, scheduled when the source's period came around
+type IngestionRunTrigger string
+
 // NodeEntry defines model for NodeEntry.
 type NodeEntry struct {
-	IpAddr  string            `json:"ip_addr"`
-	Sources []NodeSourceEntry `json:"sources"`
+	// FirstSeen When any source first reported the node
+	FirstSeen string `json:"first_seen"`
+	IpAddr    string `json:"ip_addr"`
+
+	// LastSeen When a source last reported the node
+	LastSeen string            `json:"last_seen"`
+	Sources  []NodeSourceEntry `json:"sources"`
+}
+
+// NodeHistoryEntry defines model for NodeHistoryEntry.
+type NodeHistoryEntry struct {
+	Event      NodeHistoryEntryEvent `json:"event"`
+	Id         int                   `json:"id"`
+	IpAddr     string                `json:"ip_addr"`
+	OccurredAt string                `json:"occurred_at"`
+	SourceId   int                   `json:"source_id"`
+}
+
+// NodeHistoryEntryEvent defines model for NodeHistoryEntry.Event.
+type NodeHistoryEntryEvent string
+
+// NodeLookupInput defines model for NodeLookupInput.
+type NodeLookupInput struct {
+	IpAddrs []string `json:"ip_addrs"`
+}
+
+// NodeLookupResult defines model for NodeLookupResult.
+type NodeLookupResult struct {
+	IpAddr string `json:"ip_addr"`
+
+	// Listed Whether the ip address is currently an aggregated node after applying the allowlist
+	Listed bool       `json:"listed"`
+	Node   *NodeEntry `json:"node,omitempty"`
 }
 
 // NodeSourceEntry defines model for NodeSourceEntry.
@@ -69,14 +277,63 @@ type PaginatedAllowlistEntry struct {
 	Cursor  string           `json:"cursor"`
 	Data    []AllowlistEntry `json:"data"`
 	HasMore bool             `json:"has_more"`
-	Total   int              `json:"total"`
+
+	// Total Number of items in this page
+	Total int `json:"total"`
+
+	// TotalCount Number of items across every page, only returned when requested
+	TotalCount *int `json:"total_count,omitempty"`
+}
+
+// PaginatedApiKeyEntry defines model for PaginatedApiKeyEntry.
+type PaginatedApiKeyEntry struct {
+	Cursor  string        `json:"cursor"`
+	Data    []ApiKeyEntry `json:"data"`
+	HasMore bool          `json:"has_more"`
+
+	// Total Number of items in this page
+	Total int `json:"total"`
+
+	// TotalCount Number of items across every page, only returned when requested
+	TotalCount *int `json:"total_count,omitempty"`
+}
+
+// PaginatedAuditEntry defines model for PaginatedAuditEntry.
+type PaginatedAuditEntry struct {
+	Cursor  string       `json:"cursor"`
+	Data    []AuditEntry `json:"data"`
+	HasMore bool         `json:"has_more"`
+
+	// Total Number of items in this page
+	Total int `json:"total"`
+
+	// TotalCount Number of items across every page, only returned when requested
+	TotalCount *int `json:"total_count,omitempty"`
+}
+
+// PaginatedIngestionRunEntry defines model for PaginatedIngestionRunEntry.
+type PaginatedIngestionRunEntry struct {
+	Cursor  string              `json:"cursor"`
+	Data    []IngestionRunEntry `json:"data"`
+	HasMore bool                `json:"has_more"`
+
+	// Total Number of items in this page
+	Total int `json:"total"`
+
+	// TotalCount Number of items across every page, only returned when requested
+	TotalCount *int `json:"total_count,omitempty"`
 }
 
 // PaginatedMetadata defines model for PaginatedMetadata.
 type PaginatedMetadata struct {
 	Cursor  string `json:"cursor"`
 	HasMore bool   `json:"has_more"`
-	Total   int    `json:"total"`
+
+	// Total Number of items in this page
+	Total int `json:"total"`
+
+	// TotalCount Number of items across every page, only returned when requested
+	TotalCount *int `json:"total_count,omitempty"`
 }
 
 // PaginatedNodeEntry defines model for PaginatedNodeEntry.
@@ -84,7 +341,25 @@ type PaginatedNodeEntry struct {
 	Cursor  string      `json:"cursor"`
 	Data    []NodeEntry `json:"data"`
 	HasMore bool        `json:"has_more"`
-	Total   int         `json:"total"`
+
+	// Total Number of items in this page
+	Total int `json:"total"`
+
+	// TotalCount Number of items across every page, only returned when requested
+	TotalCount *int `json:"total_count,omitempty"`
+}
+
+// PaginatedNodeHistoryEntry defines model for PaginatedNodeHistoryEntry.
+type PaginatedNodeHistoryEntry struct {
+	Cursor  string             `json:"cursor"`
+	Data    []NodeHistoryEntry `json:"data"`
+	HasMore bool               `json:"has_more"`
+
+	// Total Number of items in this page
+	Total int `json:"total"`
+
+	// TotalCount Number of items across every page, only returned when requested
+	TotalCount *int `json:"total_count,omitempty"`
 }
 
 // PaginatedSourceEntry defines model for PaginatedSourceEntry.
@@ -92,58 +367,272 @@ type PaginatedSourceEntry struct {
 	Cursor  string        `json:"cursor"`
 	Data    []SourceEntry `json:"data"`
 	HasMore bool          `json:"has_more"`
-	Total   int           `json:"total"`
+
+	// Total Number of items in this page
+	Total int `json:"total"`
+
+	// TotalCount Number of items across every page, only returned when requested
+	TotalCount *int `json:"total_count,omitempty"`
+}
+
+// ReadinessStatus defines model for ReadinessStatus.
+type ReadinessStatus struct {
+	// Database ok when the database answered, otherwise the error it failed with
+	Database string `json:"database"`
+
+	// ExpectedSchemaVersion The migration version this server was built against
+	ExpectedSchemaVersion int  `json:"expected_schema_version"`
+	Ready                 bool `json:"ready"`
+
+	// SchemaDirty Whether the last migration failed part way through
+	SchemaDirty *bool `json:"schema_dirty,omitempty"`
+
+	// SchemaVersion The migration version the database is at
+	SchemaVersion *int `json:"schema_version,omitempty"`
+}
+
+// SourceBasicAuth defines model for SourceBasicAuth.
+type SourceBasicAuth struct {
+	Password string `json:"password"`
+	Username string `json:"username"`
+}
+
+// SourceCredentials Sent with every request for the source url. Encrypted at rest and never returned, an empty object removes them
+type SourceCredentials struct {
+	BasicAuth *SourceBasicAuth `json:"basic_auth,omitempty"`
+
+	// BearerToken Sent as `Authorization: Bearer <token>`
+	BearerToken *string `json:"bearer_token,omitempty"`
+
+	// Headers Extra request headers, e.g. an API key header required by the feed
+	Headers *map[string]string `json:"headers,omitempty"`
 }
 
 // SourceEntry defines model for SourceEntry.
 type SourceEntry struct {
-	Id            int    `json:"id"`
-	LastExecution string `json:"last_execution"`
-	Name          string `json:"name"`
-	Period        string `json:"period"`
-	Running       bool   `json:"running"`
-	Url           string `json:"url"`
-	Version       int    `json:"version"`
+	// ConsecutiveFailures Number of ingestions that have failed in a row
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// Format How the document served by the source url should be parsed. Defaults to csv
+	Format        SourceFormat        `json:"format"`
+	FormatOptions SourceFormatOptions `json:"format_options"`
+
+	// HasCredentials Whether the source has credentials, they are never returned
+	HasCredentials bool `json:"has_credentials"`
+	Id             int  `json:"id"`
+
+	// LastError The error from the most recent failed ingestion, cleared on success
+	LastError     *string `json:"last_error,omitempty"`
+	LastExecution string  `json:"last_execution"`
+
+	// LastSuccess When the source was last ingested without an error
+	LastSuccess *string `json:"last_success,omitempty"`
+	Name        string  `json:"name"`
+
+	// PausedUntil Set once the source has failed too many times in a row, it is not ingested again until this time or until it is started
+	PausedUntil *string `json:"paused_until,omitempty"`
+	Period      string  `json:"period"`
+	Running     bool    `json:"running"`
+	Url         string  `json:"url"`
+	Version     int     `json:"version"`
+}
+
+// SourceFormat How the document served by the source url should be parsed. Defaults to csv
+type SourceFormat string
+
+// SourceFormatOptions defines model for SourceFormatOptions.
+type SourceFormatOptions struct {
+	// Column (csv) Zero based index of the column holding the ip address
+	Column *int `json:"column,omitempty"`
+
+	// Header (csv) Skip the first row of the document
+	Header *bool `json:"header,omitempty"`
+
+	// Path (json) Path to the ip addresses, e.g. $.relays[*].exit_addresses[*]
+	Path *string `json:"path,omitempty"`
+}
+
+// UpdateSourceEntryInput defines model for UpdateSourceEntryInput.
+type UpdateSourceEntryInput struct {
+	// Credentials Sent with every request for the source url. Encrypted at rest and never returned, an empty object removes them
+	Credentials *SourceCredentials `json:"credentials,omitempty"`
+
+	// Format How the document served by the source url should be parsed. Defaults to csv
+	Format        *SourceFormat        `json:"format,omitempty"`
+	FormatOptions *SourceFormatOptions `json:"format_options,omitempty"`
+	Name          *string              `json:"name,omitempty"`
+	Period        *string              `json:"period,omitempty"`
+	Url           *string              `json:"url,omitempty"`
+}
+
+// ListApiKeysParams defines parameters for ListApiKeys.
+type ListApiKeysParams struct {
+	// After Cursor to continue pagination from, found in the prevous request
+	After *string `form:"after,omitempty" json:"after,omitempty"`
+
+	// Limit Number of results to show
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
 // ListAllAllowlistsParams defines parameters for ListAllAllowlists.
 type ListAllAllowlistsParams struct {
+	// After Cursor to continue pagination from, found in the prevous request
+	After *string `form:"after,omitempty" json:"after,omitempty"`
+
+	// Limit Number of results to show
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListAuditLogParams defines parameters for ListAuditLog.
+type ListAuditLogParams struct {
+	// Actor Only show records made by this API key name
+	Actor *string `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// ResourceType Only show records for this type of resource
+	ResourceType *AuditResourceType `form:"resourceType,omitempty" json:"resourceType,omitempty"`
+
+	// ResourceId Only show records for this resource id, usually combined with resourceType
+	ResourceId *string `form:"resourceId,omitempty" json:"resourceId,omitempty"`
+
+	// Since Only show records that occurred at or after this time
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until Only show records that occurred before this time
+	Until *time.Time `form:"until,omitempty" json:"until,omitempty"`
+
+	// After Cursor to continue pagination from, found in the prevous request
 	After *string `form:"after,omitempty" json:"after,omitempty"`
-	Limit *int    `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Limit Number of results to show
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
 // ListAggregatedNodesParams defines parameters for ListAggregatedNodes.
 type ListAggregatedNodesParams struct {
-	AllowlistId *int    `form:"allowlistId,omitempty" json:"allowlistId,omitempty"`
-	Invert      *bool   `form:"invert,omitempty" json:"invert,omitempty"`
-	After       *string `form:"after,omitempty" json:"after,omitempty"`
-	Limit       *int    `form:"limit,omitempty" json:"limit,omitempty"`
+	// AllowlistId Filter to only show nodes that are in this allowlist
+	AllowlistId *int `form:"allowlistId,omitempty" json:"allowlistId,omitempty"`
+
+	// Invert Fitler to remove nodes found in the allowlist
+	Invert *bool `form:"invert,omitempty" json:"invert,omitempty"`
+
+	// After Cursor to continue pagination from, found in the prevous request
+	After *string `form:"after,omitempty" json:"after,omitempty"`
+
+	// Limit Number of results to show
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Count Also count every node matching the filters and return it as total_count
+	Count *bool `form:"count,omitempty" json:"count,omitempty"`
+}
+
+// ExportNodesParams defines parameters for ExportNodes.
+type ExportNodesParams struct {
+	// Format txt writes one ip per line, csv and jsonl also include the sources and when the node was last seen
+	Format *ExportNodesParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// AllowlistId Filter to only show nodes that are in this allowlist
+	AllowlistId *int `form:"allowlistId,omitempty" json:"allowlistId,omitempty"`
+
+	// Invert Fitler to remove nodes found in the allowlist
+	Invert *bool `form:"invert,omitempty" json:"invert,omitempty"`
+}
+
+// ExportNodesParamsFormat defines parameters for ExportNodes.
+type ExportNodesParamsFormat string
+
+// LookupNodesParams defines parameters for LookupNodes.
+type LookupNodesParams struct {
+	// AllowlistId Filter to only show nodes that are in this allowlist
+	AllowlistId *int `form:"allowlistId,omitempty" json:"allowlistId,omitempty"`
+
+	// Invert Fitler to remove nodes found in the allowlist
+	Invert *bool `form:"invert,omitempty" json:"invert,omitempty"`
+}
+
+// GetNodeParams defines parameters for GetNode.
+type GetNodeParams struct {
+	// AllowlistId Filter to only show nodes that are in this allowlist
+	AllowlistId *int `form:"allowlistId,omitempty" json:"allowlistId,omitempty"`
+
+	// Invert Fitler to remove nodes found in the allowlist
+	Invert *bool `form:"invert,omitempty" json:"invert,omitempty"`
+}
+
+// ListNodeHistoryParams defines parameters for ListNodeHistory.
+type ListNodeHistoryParams struct {
+	// SourceId Only show the history of the node in this source
+	SourceId *int `form:"sourceId,omitempty" json:"sourceId,omitempty"`
+
+	// After Cursor to continue pagination from, found in the prevous request
+	After *string `form:"after,omitempty" json:"after,omitempty"`
+
+	// Limit Number of results to show
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
 // ListSourcesParams defines parameters for ListSources.
 type ListSourcesParams struct {
+	// After Cursor to continue pagination from, found in the prevous request
 	After *string `form:"after,omitempty" json:"after,omitempty"`
-	Limit *int    `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Limit Number of results to show
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
 // ListSourceNodesParams defines parameters for ListSourceNodes.
 type ListSourceNodesParams struct {
+	// After Cursor to continue pagination from, found in the prevous request
+	After *string `form:"after,omitempty" json:"after,omitempty"`
+
+	// Limit Number of results to show
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Count Also count every node of the source and return it as total_count
+	Count *bool `form:"count,omitempty" json:"count,omitempty"`
+}
+
+// ListSourceIngestionRunsParams defines parameters for ListSourceIngestionRuns.
+type ListSourceIngestionRunsParams struct {
+	// After Cursor to continue pagination from, found in the prevous request
 	After *string `form:"after,omitempty" json:"after,omitempty"`
-	Limit *int    `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Limit Number of results to show
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
+// CreateApiKeyJSONRequestBody defines body for CreateApiKey for application/json ContentType.
+type CreateApiKeyJSONRequestBody = CreateApiKeyInput
+
 // CreateAllowlistJSONRequestBody defines body for CreateAllowlist for application/json ContentType.
 type CreateAllowlistJSONRequestBody = CreateAllowlistInput
 
 // AddToAllowlistJSONRequestBody defines body for AddToAllowlist for application/json ContentType.
 type AddToAllowlistJSONRequestBody = AddAllowlistEntryInput
 
+// LookupNodesJSONRequestBody defines body for LookupNodes for application/json ContentType.
+type LookupNodesJSONRequestBody = NodeLookupInput
+
 // CreateSourceJSONRequestBody defines body for CreateSource for application/json ContentType.
 type CreateSourceJSONRequestBody = CreateSourceEntryInput
 
+// UpdateSourceJSONRequestBody defines body for UpdateSource for application/json ContentType.
+type UpdateSourceJSONRequestBody = UpdateSourceEntryInput
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /admin/keys)
+	ListApiKeys(w http.ResponseWriter, r *http.Request, params ListApiKeysParams)
+
+	// (POST /admin/keys)
+	CreateApiKey(w http.ResponseWriter, r *http.Request)
+
+	// (DELETE /admin/keys/{id})
+	RevokeApiKey(w http.ResponseWriter, r *http.Request, id int)
+
+	// (POST /admin/keys/{id}/rotate)
+	RotateApiKey(w http.ResponseWriter, r *http.Request, id int)
+
 	// (GET /allowlist)
 	ListAllAllowlists(w http.ResponseWriter, r *http.Request, params ListAllAllowlistsParams)
 
@@ -162,29 +651,85 @@ type ServerInterface interface {
 	// (DELETE /allowlist/{id}/entry/{entryId})
 	RemoveFromAllowlist(w http.ResponseWriter, r *http.Request, id int, entryId int)
 
+	// (GET /audit)
+	ListAuditLog(w http.ResponseWriter, r *http.Request, params ListAuditLogParams)
+
+	// (GET /healthz)
+	GetHealth(w http.ResponseWriter, r *http.Request)
+
 	// (GET /nodes)
 	ListAggregatedNodes(w http.ResponseWriter, r *http.Request, params ListAggregatedNodesParams)
 
+	// (GET /nodes/export)
+	ExportNodes(w http.ResponseWriter, r *http.Request, params ExportNodesParams)
+
+	// (POST /nodes/lookup)
+	LookupNodes(w http.ResponseWriter, r *http.Request, params LookupNodesParams)
+
+	// (GET /nodes/{ip})
+	GetNode(w http.ResponseWriter, r *http.Request, ip string, params GetNodeParams)
+
+	// (GET /nodes/{ip}/history)
+	ListNodeHistory(w http.ResponseWriter, r *http.Request, ip string, params ListNodeHistoryParams)
+
+	// (GET /readyz)
+	GetReadiness(w http.ResponseWriter, r *http.Request)
+
+	// (GET /runs/{id})
+	GetIngestionRun(w http.ResponseWriter, r *http.Request, id int64)
+
 	// (GET /sources)
 	ListSources(w http.ResponseWriter, r *http.Request, params ListSourcesParams)
 
 	// (POST /sources)
 	CreateSource(w http.ResponseWriter, r *http.Request)
 
+	// (DELETE /sources/{id})
+	DeleteSource(w http.ResponseWriter, r *http.Request, id int)
+
 	// (GET /sources/{id})
 	ListSourceNodes(w http.ResponseWriter, r *http.Request, id int, params ListSourceNodesParams)
 
+	// (PATCH /sources/{id})
+	UpdateSource(w http.ResponseWriter, r *http.Request, id int)
+
+	// (GET /sources/{id}/runs)
+	ListSourceIngestionRuns(w http.ResponseWriter, r *http.Request, id int, params ListSourceIngestionRunsParams)
+
 	// (POST /sources/{id}/start)
 	StartSource(w http.ResponseWriter, r *http.Request, id int)
 
 	// (POST /sources/{id}/stop)
 	StopSource(w http.ResponseWriter, r *http.Request, id int)
+
+	// (POST /sources/{id}/sync)
+	SyncSource(w http.ResponseWriter, r *http.Request, id int)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
+// (GET /admin/keys)
+func (_ Unimplemented) ListApiKeys(w http.ResponseWriter, r *http.Request, params ListApiKeysParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /admin/keys)
+func (_ Unimplemented) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (DELETE /admin/keys/{id})
+func (_ Unimplemented) RevokeApiKey(w http.ResponseWriter, r *http.Request, id int) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /admin/keys/{id}/rotate)
+func (_ Unimplemented) RotateApiKey(w http.ResponseWriter, r *http.Request, id int) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // (GET /allowlist)
 func (_ Unimplemented) ListAllAllowlists(w http.ResponseWriter, r *http.Request, params ListAllAllowlistsParams) {
 	w.WriteHeader(http.StatusNotImplemented)
@@ -215,11 +760,51 @@ func (_ Unimplemented) RemoveFromAllowlist(w http.ResponseWriter, r *http.Reques
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// (GET /audit)
+func (_ Unimplemented) ListAuditLog(w http.ResponseWriter, r *http.Request, params ListAuditLogParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /healthz)
+func (_ Unimplemented) GetHealth(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // (GET /nodes)
 func (_ Unimplemented) ListAggregatedNodes(w http.ResponseWriter, r *http.Request, params ListAggregatedNodesParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// (GET /nodes/export)
+func (_ Unimplemented) ExportNodes(w http.ResponseWriter, r *http.Request, params ExportNodesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (POST /nodes/lookup)
+func (_ Unimplemented) LookupNodes(w http.ResponseWriter, r *http.Request, params LookupNodesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /nodes/{ip})
+func (_ Unimplemented) GetNode(w http.ResponseWriter, r *http.Request, ip string, params GetNodeParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /nodes/{ip}/history)
+func (_ Unimplemented) ListNodeHistory(w http.ResponseWriter, r *http.Request, ip string, params ListNodeHistoryParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /readyz)
+func (_ Unimplemented) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /runs/{id})
+func (_ Unimplemented) GetIngestionRun(w http.ResponseWriter, r *http.Request, id int64) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // (GET /sources)
 func (_ Unimplemented) ListSources(w http.ResponseWriter, r *http.Request, params ListSourcesParams) {
 	w.WriteHeader(http.StatusNotImplemented)
@@ -230,11 +815,26 @@ func (_ Unimplemented) CreateSource(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// (DELETE /sources/{id})
+func (_ Unimplemented) DeleteSource(w http.ResponseWriter, r *http.Request, id int) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // (GET /sources/{id})
 func (_ Unimplemented) ListSourceNodes(w http.ResponseWriter, r *http.Request, id int, params ListSourceNodesParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// (PATCH /sources/{id})
+func (_ Unimplemented) UpdateSource(w http.ResponseWriter, r *http.Request, id int) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// (GET /sources/{id}/runs)
+func (_ Unimplemented) ListSourceIngestionRuns(w http.ResponseWriter, r *http.Request, id int, params ListSourceIngestionRunsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // (POST /sources/{id}/start)
 func (_ Unimplemented) StartSource(w http.ResponseWriter, r *http.Request, id int) {
 	w.WriteHeader(http.StatusNotImplemented)
@@ -245,6 +845,11 @@ func (_ Unimplemented) StopSource(w http.ResponseWriter, r *http.Request, id int
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
+// (POST /sources/{id}/sync)
+func (_ Unimplemented) SyncSource(w http.ResponseWriter, r *http.Request, id int) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // ServerInterfaceWrapper converts contexts to parameters.
 type ServerInterfaceWrapper struct {
 	Handler            ServerInterface
@@ -254,14 +859,16 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
-// ListAllAllowlists operation middleware
-func (siw *ServerInterfaceWrapper) ListAllAllowlists(w http.ResponseWriter, r *http.Request) {
+// ListApiKeys operation middleware
+func (siw *ServerInterfaceWrapper) ListApiKeys(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"keys:admin"})
+
 	// Parameter object where we will unmarshal all parameters from the context
-	var params ListAllAllowlistsParams
+	var params ListApiKeysParams
 
 	// ------------- Optional query parameter "after" -------------
 
@@ -280,7 +887,7 @@ func (siw *ServerInterfaceWrapper) ListAllAllowlists(w http.ResponseWriter, r *h
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListAllAllowlists(w, r, params)
+		siw.Handler.ListApiKeys(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -290,12 +897,14 @@ func (siw *ServerInterfaceWrapper) ListAllAllowlists(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// CreateAllowlist operation middleware
-func (siw *ServerInterfaceWrapper) CreateAllowlist(w http.ResponseWriter, r *http.Request) {
+// CreateApiKey operation middleware
+func (siw *ServerInterfaceWrapper) CreateApiKey(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"keys:admin"})
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateAllowlist(w, r)
+		siw.Handler.CreateApiKey(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -305,8 +914,8 @@ func (siw *ServerInterfaceWrapper) CreateAllowlist(w http.ResponseWriter, r *htt
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// DeleteAllowList operation middleware
-func (siw *ServerInterfaceWrapper) DeleteAllowList(w http.ResponseWriter, r *http.Request) {
+// RevokeApiKey operation middleware
+func (siw *ServerInterfaceWrapper) RevokeApiKey(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -320,8 +929,10 @@ func (siw *ServerInterfaceWrapper) DeleteAllowList(w http.ResponseWriter, r *htt
 		return
 	}
 
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"keys:admin"})
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteAllowList(w, r, id)
+		siw.Handler.RevokeApiKey(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -331,8 +942,8 @@ func (siw *ServerInterfaceWrapper) DeleteAllowList(w http.ResponseWriter, r *htt
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// ListAllowlistEntries operation middleware
-func (siw *ServerInterfaceWrapper) ListAllowlistEntries(w http.ResponseWriter, r *http.Request) {
+// RotateApiKey operation middleware
+func (siw *ServerInterfaceWrapper) RotateApiKey(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -346,8 +957,10 @@ func (siw *ServerInterfaceWrapper) ListAllowlistEntries(w http.ResponseWriter, r
 		return
 	}
 
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"keys:admin"})
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListAllowlistEntries(w, r, id)
+		siw.Handler.RotateApiKey(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -357,34 +970,63 @@ func (siw *ServerInterfaceWrapper) ListAllowlistEntries(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// AddToAllowlist operation middleware
-func (siw *ServerInterfaceWrapper) AddToAllowlist(w http.ResponseWriter, r *http.Request) {
+// ListAllAllowlists operation middleware
+func (siw *ServerInterfaceWrapper) ListAllAllowlists(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id int
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"allowlist:read"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAllAllowlistsParams
+
+	// ------------- Optional query parameter "after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
 		return
 	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.AddToAllowlist(w, r, id)
-	}))
+	// ------------- Optional query parameter "limit" -------------
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAllAllowlists(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
 	}
 
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// RemoveFromAllowlist operation middleware
-func (siw *ServerInterfaceWrapper) RemoveFromAllowlist(w http.ResponseWriter, r *http.Request) {
+// CreateAllowlist operation middleware
+func (siw *ServerInterfaceWrapper) CreateAllowlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"allowlist:write"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateAllowlist(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteAllowList operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAllowList(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -398,17 +1040,38 @@ func (siw *ServerInterfaceWrapper) RemoveFromAllowlist(w http.ResponseWriter, r
 		return
 	}
 
-	// ------------- Path parameter "entryId" -------------
-	var entryId int
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"allowlist:write"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "entryId", chi.URLParam(r, "entryId"), &entryId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAllowList(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListAllowlistEntries operation middleware
+func (siw *ServerInterfaceWrapper) ListAllowlistEntries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"allowlist:read"})
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.RemoveFromAllowlist(w, r, id, entryId)
+		siw.Handler.ListAllowlistEntries(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -418,49 +1081,62 @@ func (siw *ServerInterfaceWrapper) RemoveFromAllowlist(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// ListAggregatedNodes operation middleware
-func (siw *ServerInterfaceWrapper) ListAggregatedNodes(w http.ResponseWriter, r *http.Request) {
+// AddToAllowlist operation middleware
+func (siw *ServerInterfaceWrapper) AddToAllowlist(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListAggregatedNodesParams
-
-	// ------------- Optional query parameter "allowlistId" -------------
+	// ------------- Path parameter "id" -------------
+	var id int
 
-	err = runtime.BindQueryParameter("form", true, false, "allowlistId", r.URL.Query(), &params.AllowlistId)
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "allowlistId", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "invert" -------------
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"allowlist:write"})
 
-	err = runtime.BindQueryParameter("form", true, false, "invert", r.URL.Query(), &params.Invert)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "invert", Err: err})
-		return
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AddToAllowlist(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
 	}
 
-	// ------------- Optional query parameter "after" -------------
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
 
-	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
+// RemoveFromAllowlist operation middleware
+func (siw *ServerInterfaceWrapper) RemoveFromAllowlist(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "limit" -------------
+	// ------------- Path parameter "entryId" -------------
+	var entryId int
 
-	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	err = runtime.BindStyledParameterWithOptions("simple", "entryId", chi.URLParam(r, "entryId"), &entryId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "entryId", Err: err})
 		return
 	}
 
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"allowlist:write"})
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListAggregatedNodes(w, r, params)
+		siw.Handler.RemoveFromAllowlist(w, r, id, entryId)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -470,14 +1146,56 @@ func (siw *ServerInterfaceWrapper) ListAggregatedNodes(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// ListSources operation middleware
-func (siw *ServerInterfaceWrapper) ListSources(w http.ResponseWriter, r *http.Request) {
+// ListAuditLog operation middleware
+func (siw *ServerInterfaceWrapper) ListAuditLog(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"audit:read"})
+
 	// Parameter object where we will unmarshal all parameters from the context
-	var params ListSourcesParams
+	var params ListAuditLogParams
+
+	// ------------- Optional query parameter "actor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor", r.URL.Query(), &params.Actor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "actor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "resourceType" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "resourceType", r.URL.Query(), &params.ResourceType)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "resourceType", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "resourceId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "resourceId", r.URL.Query(), &params.ResourceId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "resourceId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", r.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "since", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "until" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "until", r.URL.Query(), &params.Until)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "until", Err: err})
+		return
+	}
 
 	// ------------- Optional query parameter "after" -------------
 
@@ -496,7 +1214,7 @@ func (siw *ServerInterfaceWrapper) ListSources(w http.ResponseWriter, r *http.Re
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListSources(w, r, params)
+		siw.Handler.ListAuditLog(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -506,12 +1224,12 @@ func (siw *ServerInterfaceWrapper) ListSources(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// CreateSource operation middleware
-func (siw *ServerInterfaceWrapper) CreateSource(w http.ResponseWriter, r *http.Request) {
+// GetHealth operation middleware
+func (siw *ServerInterfaceWrapper) GetHealth(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateSource(w, r)
+		siw.Handler.GetHealth(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -521,23 +1239,32 @@ func (siw *ServerInterfaceWrapper) CreateSource(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// ListSourceNodes operation middleware
-func (siw *ServerInterfaceWrapper) ListSourceNodes(w http.ResponseWriter, r *http.Request) {
+// ListAggregatedNodes operation middleware
+func (siw *ServerInterfaceWrapper) ListAggregatedNodes(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id int
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"nodes:read"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAggregatedNodesParams
+
+	// ------------- Optional query parameter "allowlistId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "allowlistId", r.URL.Query(), &params.AllowlistId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "allowlistId", Err: err})
 		return
 	}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListSourceNodesParams
+	// ------------- Optional query parameter "invert" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "invert", r.URL.Query(), &params.Invert)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "invert", Err: err})
+		return
+	}
 
 	// ------------- Optional query parameter "after" -------------
 
@@ -555,8 +1282,16 @@ func (siw *ServerInterfaceWrapper) ListSourceNodes(w http.ResponseWriter, r *htt
 		return
 	}
 
+	// ------------- Optional query parameter "count" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "count", r.URL.Query(), &params.Count)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "count", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListSourceNodes(w, r, id, params)
+		siw.Handler.ListAggregatedNodes(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -566,23 +1301,43 @@ func (siw *ServerInterfaceWrapper) ListSourceNodes(w http.ResponseWriter, r *htt
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// StartSource operation middleware
-func (siw *ServerInterfaceWrapper) StartSource(w http.ResponseWriter, r *http.Request) {
+// ExportNodes operation middleware
+func (siw *ServerInterfaceWrapper) ExportNodes(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id int
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"nodes:read"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportNodesParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "allowlistId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "allowlistId", r.URL.Query(), &params.AllowlistId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "allowlistId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "invert" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "invert", r.URL.Query(), &params.Invert)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "invert", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.StartSource(w, r, id)
+		siw.Handler.ExportNodes(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -592,23 +1347,35 @@ func (siw *ServerInterfaceWrapper) StartSource(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// StopSource operation middleware
-func (siw *ServerInterfaceWrapper) StopSource(w http.ResponseWriter, r *http.Request) {
+// LookupNodes operation middleware
+func (siw *ServerInterfaceWrapper) LookupNodes(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id int
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"nodes:read"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// Parameter object where we will unmarshal all parameters from the context
+	var params LookupNodesParams
+
+	// ------------- Optional query parameter "allowlistId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "allowlistId", r.URL.Query(), &params.AllowlistId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "allowlistId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "invert" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "invert", r.URL.Query(), &params.Invert)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "invert", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.StopSource(w, r, id)
+		siw.Handler.LookupNodes(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -618,179 +1385,1136 @@ func (siw *ServerInterfaceWrapper) StopSource(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+// GetNode operation middleware
+func (siw *ServerInterfaceWrapper) GetNode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+	var err error
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	// ------------- Path parameter "ip" -------------
+	var ip string
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
-}
+	err = runtime.BindStyledParameterWithOptions("simple", "ip", chi.URLParam(r, "ip"), &ip, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "ip", Err: err})
+		return
+	}
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"nodes:read"})
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetNodeParams
 
-type RequiredParamError struct {
-	ParamName string
-}
+	// ------------- Optional query parameter "allowlistId" -------------
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
-}
+	err = runtime.BindQueryParameter("form", true, false, "allowlistId", r.URL.Query(), &params.AllowlistId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "allowlistId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "invert" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "invert", r.URL.Query(), &params.Invert)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "invert", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetNode(w, r, ip, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListNodeHistory operation middleware
+func (siw *ServerInterfaceWrapper) ListNodeHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "ip" -------------
+	var ip string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "ip", chi.URLParam(r, "ip"), &ip, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "ip", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"nodes:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListNodeHistoryParams
+
+	// ------------- Optional query parameter "sourceId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sourceId", r.URL.Query(), &params.SourceId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sourceId", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListNodeHistory(w, r, ip, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetReadiness operation middleware
+func (siw *ServerInterfaceWrapper) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetReadiness(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetIngestionRun operation middleware
+func (siw *ServerInterfaceWrapper) GetIngestionRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"sources:read"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetIngestionRun(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListSources operation middleware
+func (siw *ServerInterfaceWrapper) ListSources(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"sources:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListSourcesParams
+
+	// ------------- Optional query parameter "after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListSources(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateSource operation middleware
+func (siw *ServerInterfaceWrapper) CreateSource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"sources:admin"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateSource(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteSource operation middleware
+func (siw *ServerInterfaceWrapper) DeleteSource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"sources:admin"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteSource(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListSourceNodes operation middleware
+func (siw *ServerInterfaceWrapper) ListSourceNodes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"sources:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListSourceNodesParams
+
+	// ------------- Optional query parameter "after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "count" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "count", r.URL.Query(), &params.Count)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "count", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListSourceNodes(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateSource operation middleware
+func (siw *ServerInterfaceWrapper) UpdateSource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"sources:admin"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateSource(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ListSourceIngestionRuns operation middleware
+func (siw *ServerInterfaceWrapper) ListSourceIngestionRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"sources:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListSourceIngestionRunsParams
+
+	// ------------- Optional query parameter "after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListSourceIngestionRuns(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// StartSource operation middleware
+func (siw *ServerInterfaceWrapper) StartSource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"sources:admin"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StartSource(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// StopSource operation middleware
+func (siw *ServerInterfaceWrapper) StopSource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"sources:admin"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StopSource(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SyncSource operation middleware
+func (siw *ServerInterfaceWrapper) SyncSource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx = context.WithValue(ctx, ApiKeyScopes, []string{"sources:admin"})
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SyncSource(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/keys", wrapper.ListApiKeys)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/keys", wrapper.CreateApiKey)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/keys/{id}", wrapper.RevokeApiKey)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/keys/{id}/rotate", wrapper.RotateApiKey)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/allowlist", wrapper.ListAllAllowlists)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/allowlist", wrapper.CreateAllowlist)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/allowlist/{id}", wrapper.DeleteAllowList)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/allowlist/{id}/entry", wrapper.ListAllowlistEntries)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/allowlist/{id}/entry", wrapper.AddToAllowlist)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/allowlist/{id}/entry/{entryId}", wrapper.RemoveFromAllowlist)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/audit", wrapper.ListAuditLog)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/healthz", wrapper.GetHealth)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/nodes", wrapper.ListAggregatedNodes)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/nodes/export", wrapper.ExportNodes)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/nodes/lookup", wrapper.LookupNodes)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/nodes/{ip}", wrapper.GetNode)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/nodes/{ip}/history", wrapper.ListNodeHistory)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/readyz", wrapper.GetReadiness)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/runs/{id}", wrapper.GetIngestionRun)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sources", wrapper.ListSources)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sources", wrapper.CreateSource)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/sources/{id}", wrapper.DeleteSource)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sources/{id}", wrapper.ListSourceNodes)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/sources/{id}", wrapper.UpdateSource)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/sources/{id}/runs", wrapper.ListSourceIngestionRuns)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sources/{id}/start", wrapper.StartSource)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sources/{id}/stop", wrapper.StopSource)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/sources/{id}/sync", wrapper.SyncSource)
+	})
+
+	return r
+}
+
+type ListApiKeysRequestObject struct {
+	Params ListApiKeysParams
+}
+
+type ListApiKeysResponseObject interface {
+	VisitListApiKeysResponse(w http.ResponseWriter) error
+}
+
+type ListApiKeys200JSONResponse PaginatedApiKeyEntry
+
+func (response ListApiKeys200JSONResponse) VisitListApiKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListApiKeys400TextResponse string
+
+func (response ListApiKeys400TextResponse) VisitListApiKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type CreateApiKeyRequestObject struct {
+	Body *CreateApiKeyJSONRequestBody
+}
+
+type CreateApiKeyResponseObject interface {
+	VisitCreateApiKeyResponse(w http.ResponseWriter) error
+}
+
+type CreateApiKey201JSONResponse CreatedApiKeyEntry
+
+func (response CreateApiKey201JSONResponse) VisitCreateApiKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateApiKey400TextResponse string
+
+func (response CreateApiKey400TextResponse) VisitCreateApiKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type RevokeApiKeyRequestObject struct {
+	Id int `json:"id"`
+}
+
+type RevokeApiKeyResponseObject interface {
+	VisitRevokeApiKeyResponse(w http.ResponseWriter) error
+}
+
+type RevokeApiKey204Response struct {
+}
+
+func (response RevokeApiKey204Response) VisitRevokeApiKeyResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type RevokeApiKey400TextResponse string
+
+func (response RevokeApiKey400TextResponse) VisitRevokeApiKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type RevokeApiKey404TextResponse string
+
+func (response RevokeApiKey404TextResponse) VisitRevokeApiKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(404)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type RotateApiKeyRequestObject struct {
+	Id int `json:"id"`
+}
+
+type RotateApiKeyResponseObject interface {
+	VisitRotateApiKeyResponse(w http.ResponseWriter) error
+}
+
+type RotateApiKey201JSONResponse CreatedApiKeyEntry
+
+func (response RotateApiKey201JSONResponse) VisitRotateApiKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RotateApiKey400TextResponse string
+
+func (response RotateApiKey400TextResponse) VisitRotateApiKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type RotateApiKey404TextResponse string
+
+func (response RotateApiKey404TextResponse) VisitRotateApiKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(404)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type ListAllAllowlistsRequestObject struct {
+	Params ListAllAllowlistsParams
+}
+
+type ListAllAllowlistsResponseObject interface {
+	VisitListAllAllowlistsResponse(w http.ResponseWriter) error
+}
+
+type ListAllAllowlists200JSONResponse PaginatedAllowlistEntry
+
+func (response ListAllAllowlists200JSONResponse) VisitListAllAllowlistsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAllAllowlists400TextResponse string
+
+func (response ListAllAllowlists400TextResponse) VisitListAllAllowlistsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type CreateAllowlistRequestObject struct {
+	Body *CreateAllowlistJSONRequestBody
+}
+
+type CreateAllowlistResponseObject interface {
+	VisitCreateAllowlistResponse(w http.ResponseWriter) error
+}
+
+type CreateAllowlist201JSONResponse AllowlistEntry
+
+func (response CreateAllowlist201JSONResponse) VisitCreateAllowlistResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAllowlist400TextResponse string
+
+func (response CreateAllowlist400TextResponse) VisitCreateAllowlistResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type DeleteAllowListRequestObject struct {
+	Id int `json:"id"`
+}
+
+type DeleteAllowListResponseObject interface {
+	VisitDeleteAllowListResponse(w http.ResponseWriter) error
+}
+
+type DeleteAllowList204Response struct {
+}
+
+func (response DeleteAllowList204Response) VisitDeleteAllowListResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteAllowList400TextResponse string
+
+func (response DeleteAllowList400TextResponse) VisitDeleteAllowListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type DeleteAllowList404TextResponse string
+
+func (response DeleteAllowList404TextResponse) VisitDeleteAllowListResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(404)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type ListAllowlistEntriesRequestObject struct {
+	Id int `json:"id"`
+}
+
+type ListAllowlistEntriesResponseObject interface {
+	VisitListAllowlistEntriesResponse(w http.ResponseWriter) error
+}
+
+type ListAllowlistEntries200JSONResponse []AllowlistEntryItem
+
+func (response ListAllowlistEntries200JSONResponse) VisitListAllowlistEntriesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAllowlistEntries400TextResponse string
+
+func (response ListAllowlistEntries400TextResponse) VisitListAllowlistEntriesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type ListAllowlistEntries404TextResponse string
+
+func (response ListAllowlistEntries404TextResponse) VisitListAllowlistEntriesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(404)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type AddToAllowlistRequestObject struct {
+	Id   int `json:"id"`
+	Body *AddToAllowlistJSONRequestBody
+}
+
+type AddToAllowlistResponseObject interface {
+	VisitAddToAllowlistResponse(w http.ResponseWriter) error
+}
+
+type AddToAllowlist201JSONResponse AllowlistEntryItem
+
+func (response AddToAllowlist201JSONResponse) VisitAddToAllowlistResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AddToAllowlist400TextResponse string
+
+func (response AddToAllowlist400TextResponse) VisitAddToAllowlistResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type AddToAllowlist404TextResponse string
+
+func (response AddToAllowlist404TextResponse) VisitAddToAllowlistResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(404)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type RemoveFromAllowlistRequestObject struct {
+	Id      int `json:"id"`
+	EntryId int `json:"entryId"`
+}
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
+type RemoveFromAllowlistResponseObject interface {
+	VisitRemoveFromAllowlistResponse(w http.ResponseWriter) error
 }
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+type RemoveFromAllowlist204Response struct {
 }
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
+func (response RemoveFromAllowlist204Response) VisitRemoveFromAllowlistResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
 }
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
+type RemoveFromAllowlist400TextResponse string
+
+func (response RemoveFromAllowlist400TextResponse) VisitRemoveFromAllowlistResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
 }
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+type RemoveFromAllowlist404TextResponse string
+
+func (response RemoveFromAllowlist404TextResponse) VisitRemoveFromAllowlistResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(404)
+
+	_, err := w.Write([]byte(response))
+	return err
 }
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
+type ListAuditLogRequestObject struct {
+	Params ListAuditLogParams
 }
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
+type ListAuditLogResponseObject interface {
+	VisitListAuditLogResponse(w http.ResponseWriter) error
 }
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+type ListAuditLog200JSONResponse PaginatedAuditEntry
+
+func (response ListAuditLog200JSONResponse) VisitListAuditLogResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{})
+type ListAuditLog400TextResponse string
+
+func (response ListAuditLog400TextResponse) VisitListAuditLogResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
 }
 
-type ChiServerOptions struct {
-	BaseURL          string
-	BaseRouter       chi.Router
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+type GetHealthRequestObject struct {
 }
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseRouter: r,
-	})
+type GetHealthResponseObject interface {
+	VisitGetHealthResponse(w http.ResponseWriter) error
 }
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: r,
-	})
+type GetHealth200TextResponse string
+
+func (response GetHealth200TextResponse) VisitGetHealthResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(200)
+
+	_, err := w.Write([]byte(response))
+	return err
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
-	r := options.BaseRouter
+type ListAggregatedNodesRequestObject struct {
+	Params ListAggregatedNodesParams
+}
 
-	if r == nil {
-		r = chi.NewRouter()
+type ListAggregatedNodesResponseObject interface {
+	VisitListAggregatedNodesResponse(w http.ResponseWriter) error
+}
+
+type ListAggregatedNodes200JSONResponse PaginatedNodeEntry
+
+func (response ListAggregatedNodes200JSONResponse) VisitListAggregatedNodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAggregatedNodes400TextResponse string
+
+func (response ListAggregatedNodes400TextResponse) VisitListAggregatedNodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type ExportNodesRequestObject struct {
+	Params ExportNodesParams
+}
+
+type ExportNodesResponseObject interface {
+	VisitExportNodesResponse(w http.ResponseWriter) error
+}
+
+type ExportNodes200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response ExportNodes200ApplicationxNdjsonResponse) VisitExportNodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
 	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
 	}
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportNodes200TextcsvResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response ExportNodes200TextcsvResponse) VisitExportNodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/csv")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
 	}
+	w.WriteHeader(200)
 
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/allowlist", wrapper.ListAllAllowlists)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/allowlist", wrapper.CreateAllowlist)
-	})
-	r.Group(func(r chi.Router) {
-		r.Delete(options.BaseURL+"/allowlist/{id}", wrapper.DeleteAllowList)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/allowlist/{id}/entry", wrapper.ListAllowlistEntries)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/allowlist/{id}/entry", wrapper.AddToAllowlist)
-	})
-	r.Group(func(r chi.Router) {
-		r.Delete(options.BaseURL+"/allowlist/{id}/entry/{entryId}", wrapper.RemoveFromAllowlist)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/nodes", wrapper.ListAggregatedNodes)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/sources", wrapper.ListSources)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/sources", wrapper.CreateSource)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/sources/{id}", wrapper.ListSourceNodes)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/sources/{id}/start", wrapper.StartSource)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/sources/{id}/stop", wrapper.StopSource)
-	})
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
 
-	return r
+type ExportNodes200TextResponse string
+
+func (response ExportNodes200TextResponse) VisitExportNodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(200)
+
+	_, err := w.Write([]byte(response))
+	return err
 }
 
-type ListAllAllowlistsRequestObject struct {
-	Params ListAllAllowlistsParams
+type ExportNodes400TextResponse string
+
+func (response ExportNodes400TextResponse) VisitExportNodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
 }
 
-type ListAllAllowlistsResponseObject interface {
-	VisitListAllAllowlistsResponse(w http.ResponseWriter) error
+type LookupNodesRequestObject struct {
+	Params LookupNodesParams
+	Body   *LookupNodesJSONRequestBody
 }
 
-type ListAllAllowlists200JSONResponse PaginatedAllowlistEntry
+type LookupNodesResponseObject interface {
+	VisitLookupNodesResponse(w http.ResponseWriter) error
+}
 
-func (response ListAllAllowlists200JSONResponse) VisitListAllAllowlistsResponse(w http.ResponseWriter) error {
+type LookupNodes200JSONResponse []NodeLookupResult
+
+func (response LookupNodes200JSONResponse) VisitLookupNodesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type ListAllAllowlists400TextResponse string
+type LookupNodes400TextResponse string
 
-func (response ListAllAllowlists400TextResponse) VisitListAllAllowlistsResponse(w http.ResponseWriter) error {
+func (response LookupNodes400TextResponse) VisitLookupNodesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(400)
 
@@ -798,26 +2522,27 @@ func (response ListAllAllowlists400TextResponse) VisitListAllAllowlistsResponse(
 	return err
 }
 
-type CreateAllowlistRequestObject struct {
-	Body *CreateAllowlistJSONRequestBody
+type GetNodeRequestObject struct {
+	Ip     string `json:"ip"`
+	Params GetNodeParams
 }
 
-type CreateAllowlistResponseObject interface {
-	VisitCreateAllowlistResponse(w http.ResponseWriter) error
+type GetNodeResponseObject interface {
+	VisitGetNodeResponse(w http.ResponseWriter) error
 }
 
-type CreateAllowlist201JSONResponse AllowlistEntry
+type GetNode200JSONResponse NodeEntry
 
-func (response CreateAllowlist201JSONResponse) VisitCreateAllowlistResponse(w http.ResponseWriter) error {
+func (response GetNode200JSONResponse) VisitGetNodeResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(201)
+	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateAllowlist400TextResponse string
+type GetNode400TextResponse string
 
-func (response CreateAllowlist400TextResponse) VisitCreateAllowlistResponse(w http.ResponseWriter) error {
+func (response GetNode400TextResponse) VisitGetNodeResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(400)
 
@@ -825,25 +2550,89 @@ func (response CreateAllowlist400TextResponse) VisitCreateAllowlistResponse(w ht
 	return err
 }
 
-type DeleteAllowListRequestObject struct {
-	Id int `json:"id"`
+type GetNode404TextResponse string
+
+func (response GetNode404TextResponse) VisitGetNodeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(404)
+
+	_, err := w.Write([]byte(response))
+	return err
 }
 
-type DeleteAllowListResponseObject interface {
-	VisitDeleteAllowListResponse(w http.ResponseWriter) error
+type ListNodeHistoryRequestObject struct {
+	Ip     string `json:"ip"`
+	Params ListNodeHistoryParams
 }
 
-type DeleteAllowList204Response struct {
+type ListNodeHistoryResponseObject interface {
+	VisitListNodeHistoryResponse(w http.ResponseWriter) error
 }
 
-func (response DeleteAllowList204Response) VisitDeleteAllowListResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
+type ListNodeHistory200JSONResponse PaginatedNodeHistoryEntry
+
+func (response ListNodeHistory200JSONResponse) VisitListNodeHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListNodeHistory400TextResponse string
+
+func (response ListNodeHistory400TextResponse) VisitListNodeHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type GetReadinessRequestObject struct {
+}
+
+type GetReadinessResponseObject interface {
+	VisitGetReadinessResponse(w http.ResponseWriter) error
+}
+
+type GetReadiness200JSONResponse ReadinessStatus
+
+func (response GetReadiness200JSONResponse) VisitGetReadinessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetReadiness503JSONResponse ReadinessStatus
+
+func (response GetReadiness503JSONResponse) VisitGetReadinessResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(503)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetIngestionRunRequestObject struct {
+	Id int64 `json:"id"`
+}
+
+type GetIngestionRunResponseObject interface {
+	VisitGetIngestionRunResponse(w http.ResponseWriter) error
+}
+
+type GetIngestionRun200JSONResponse IngestionRunEntry
+
+func (response GetIngestionRun200JSONResponse) VisitGetIngestionRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-type DeleteAllowList400TextResponse string
+type GetIngestionRun400TextResponse string
 
-func (response DeleteAllowList400TextResponse) VisitDeleteAllowListResponse(w http.ResponseWriter) error {
+func (response GetIngestionRun400TextResponse) VisitGetIngestionRunResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(400)
 
@@ -851,9 +2640,9 @@ func (response DeleteAllowList400TextResponse) VisitDeleteAllowListResponse(w ht
 	return err
 }
 
-type DeleteAllowList404TextResponse string
+type GetIngestionRun404TextResponse string
 
-func (response DeleteAllowList404TextResponse) VisitDeleteAllowListResponse(w http.ResponseWriter) error {
+func (response GetIngestionRun404TextResponse) VisitGetIngestionRunResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(404)
 
@@ -861,26 +2650,26 @@ func (response DeleteAllowList404TextResponse) VisitDeleteAllowListResponse(w ht
 	return err
 }
 
-type ListAllowlistEntriesRequestObject struct {
-	Id int `json:"id"`
+type ListSourcesRequestObject struct {
+	Params ListSourcesParams
 }
 
-type ListAllowlistEntriesResponseObject interface {
-	VisitListAllowlistEntriesResponse(w http.ResponseWriter) error
+type ListSourcesResponseObject interface {
+	VisitListSourcesResponse(w http.ResponseWriter) error
 }
 
-type ListAllowlistEntries200JSONResponse []AllowlistEntryItem
+type ListSources200JSONResponse PaginatedSourceEntry
 
-func (response ListAllowlistEntries200JSONResponse) VisitListAllowlistEntriesResponse(w http.ResponseWriter) error {
+func (response ListSources200JSONResponse) VisitListSourcesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type ListAllowlistEntries400TextResponse string
+type ListSources400TextResponse string
 
-func (response ListAllowlistEntries400TextResponse) VisitListAllowlistEntriesResponse(w http.ResponseWriter) error {
+func (response ListSources400TextResponse) VisitListSourcesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(400)
 
@@ -888,37 +2677,26 @@ func (response ListAllowlistEntries400TextResponse) VisitListAllowlistEntriesRes
 	return err
 }
 
-type ListAllowlistEntries404TextResponse string
-
-func (response ListAllowlistEntries404TextResponse) VisitListAllowlistEntriesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "text/plain")
-	w.WriteHeader(404)
-
-	_, err := w.Write([]byte(response))
-	return err
-}
-
-type AddToAllowlistRequestObject struct {
-	Id   int `json:"id"`
-	Body *AddToAllowlistJSONRequestBody
+type CreateSourceRequestObject struct {
+	Body *CreateSourceJSONRequestBody
 }
 
-type AddToAllowlistResponseObject interface {
-	VisitAddToAllowlistResponse(w http.ResponseWriter) error
+type CreateSourceResponseObject interface {
+	VisitCreateSourceResponse(w http.ResponseWriter) error
 }
 
-type AddToAllowlist201JSONResponse AllowlistEntryItem
+type CreateSource201JSONResponse SourceEntry
 
-func (response AddToAllowlist201JSONResponse) VisitAddToAllowlistResponse(w http.ResponseWriter) error {
+func (response CreateSource201JSONResponse) VisitCreateSourceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(201)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type AddToAllowlist400TextResponse string
+type CreateSource400TextResponse string
 
-func (response AddToAllowlist400TextResponse) VisitAddToAllowlistResponse(w http.ResponseWriter) error {
+func (response CreateSource400TextResponse) VisitCreateSourceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(400)
 
@@ -926,36 +2704,35 @@ func (response AddToAllowlist400TextResponse) VisitAddToAllowlistResponse(w http
 	return err
 }
 
-type AddToAllowlist404TextResponse string
+type CreateSource409TextResponse string
 
-func (response AddToAllowlist404TextResponse) VisitAddToAllowlistResponse(w http.ResponseWriter) error {
+func (response CreateSource409TextResponse) VisitCreateSourceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
-	w.WriteHeader(404)
+	w.WriteHeader(409)
 
 	_, err := w.Write([]byte(response))
 	return err
 }
 
-type RemoveFromAllowlistRequestObject struct {
-	Id      int `json:"id"`
-	EntryId int `json:"entryId"`
+type DeleteSourceRequestObject struct {
+	Id int `json:"id"`
 }
 
-type RemoveFromAllowlistResponseObject interface {
-	VisitRemoveFromAllowlistResponse(w http.ResponseWriter) error
+type DeleteSourceResponseObject interface {
+	VisitDeleteSourceResponse(w http.ResponseWriter) error
 }
 
-type RemoveFromAllowlist204Response struct {
+type DeleteSource204Response struct {
 }
 
-func (response RemoveFromAllowlist204Response) VisitRemoveFromAllowlistResponse(w http.ResponseWriter) error {
+func (response DeleteSource204Response) VisitDeleteSourceResponse(w http.ResponseWriter) error {
 	w.WriteHeader(204)
 	return nil
 }
 
-type RemoveFromAllowlist400TextResponse string
+type DeleteSource400TextResponse string
 
-func (response RemoveFromAllowlist400TextResponse) VisitRemoveFromAllowlistResponse(w http.ResponseWriter) error {
+func (response DeleteSource400TextResponse) VisitDeleteSourceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(400)
 
@@ -963,9 +2740,9 @@ func (response RemoveFromAllowlist400TextResponse) VisitRemoveFromAllowlistRespo
 	return err
 }
 
-type RemoveFromAllowlist404TextResponse string
+type DeleteSource404TextResponse string
 
-func (response RemoveFromAllowlist404TextResponse) VisitRemoveFromAllowlistResponse(w http.ResponseWriter) error {
+func (response DeleteSource404TextResponse) VisitDeleteSourceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(404)
 
@@ -973,26 +2750,27 @@ func (response RemoveFromAllowlist404TextResponse) VisitRemoveFromAllowlistRespo
 	return err
 }
 
-type ListAggregatedNodesRequestObject struct {
-	Params ListAggregatedNodesParams
+type ListSourceNodesRequestObject struct {
+	Id     int `json:"id"`
+	Params ListSourceNodesParams
 }
 
-type ListAggregatedNodesResponseObject interface {
-	VisitListAggregatedNodesResponse(w http.ResponseWriter) error
+type ListSourceNodesResponseObject interface {
+	VisitListSourceNodesResponse(w http.ResponseWriter) error
 }
 
-type ListAggregatedNodes200JSONResponse PaginatedNodeEntry
+type ListSourceNodes200JSONResponse PaginatedNodeEntry
 
-func (response ListAggregatedNodes200JSONResponse) VisitListAggregatedNodesResponse(w http.ResponseWriter) error {
+func (response ListSourceNodes200JSONResponse) VisitListSourceNodesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type ListAggregatedNodes400TextResponse string
+type ListSourceNodes400TextResponse string
 
-func (response ListAggregatedNodes400TextResponse) VisitListAggregatedNodesResponse(w http.ResponseWriter) error {
+func (response ListSourceNodes400TextResponse) VisitListSourceNodesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(400)
 
@@ -1000,26 +2778,37 @@ func (response ListAggregatedNodes400TextResponse) VisitListAggregatedNodesRespo
 	return err
 }
 
-type ListSourcesRequestObject struct {
-	Params ListSourcesParams
+type ListSourceNodes404TextResponse string
+
+func (response ListSourceNodes404TextResponse) VisitListSourceNodesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(404)
+
+	_, err := w.Write([]byte(response))
+	return err
 }
 
-type ListSourcesResponseObject interface {
-	VisitListSourcesResponse(w http.ResponseWriter) error
+type UpdateSourceRequestObject struct {
+	Id   int `json:"id"`
+	Body *UpdateSourceJSONRequestBody
 }
 
-type ListSources200JSONResponse PaginatedSourceEntry
+type UpdateSourceResponseObject interface {
+	VisitUpdateSourceResponse(w http.ResponseWriter) error
+}
 
-func (response ListSources200JSONResponse) VisitListSourcesResponse(w http.ResponseWriter) error {
+type UpdateSource200JSONResponse SourceEntry
+
+func (response UpdateSource200JSONResponse) VisitUpdateSourceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type ListSources400TextResponse string
+type UpdateSource400TextResponse string
 
-func (response ListSources400TextResponse) VisitListSourcesResponse(w http.ResponseWriter) error {
+func (response UpdateSource400TextResponse) VisitUpdateSourceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(400)
 
@@ -1027,54 +2816,47 @@ func (response ListSources400TextResponse) VisitListSourcesResponse(w http.Respo
 	return err
 }
 
-type CreateSourceRequestObject struct {
-	Body *CreateSourceJSONRequestBody
-}
-
-type CreateSourceResponseObject interface {
-	VisitCreateSourceResponse(w http.ResponseWriter) error
-}
-
-type CreateSource201JSONResponse SourceEntry
+type UpdateSource404TextResponse string
 
-func (response CreateSource201JSONResponse) VisitCreateSourceResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(201)
+func (response UpdateSource404TextResponse) VisitUpdateSourceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(404)
 
-	return json.NewEncoder(w).Encode(response)
+	_, err := w.Write([]byte(response))
+	return err
 }
 
-type CreateSource400TextResponse string
+type UpdateSource409TextResponse string
 
-func (response CreateSource400TextResponse) VisitCreateSourceResponse(w http.ResponseWriter) error {
+func (response UpdateSource409TextResponse) VisitUpdateSourceResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
-	w.WriteHeader(400)
+	w.WriteHeader(409)
 
 	_, err := w.Write([]byte(response))
 	return err
 }
 
-type ListSourceNodesRequestObject struct {
+type ListSourceIngestionRunsRequestObject struct {
 	Id     int `json:"id"`
-	Params ListSourceNodesParams
+	Params ListSourceIngestionRunsParams
 }
 
-type ListSourceNodesResponseObject interface {
-	VisitListSourceNodesResponse(w http.ResponseWriter) error
+type ListSourceIngestionRunsResponseObject interface {
+	VisitListSourceIngestionRunsResponse(w http.ResponseWriter) error
 }
 
-type ListSourceNodes200JSONResponse PaginatedNodeEntry
+type ListSourceIngestionRuns200JSONResponse PaginatedIngestionRunEntry
 
-func (response ListSourceNodes200JSONResponse) VisitListSourceNodesResponse(w http.ResponseWriter) error {
+func (response ListSourceIngestionRuns200JSONResponse) VisitListSourceIngestionRunsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type ListSourceNodes400TextResponse string
+type ListSourceIngestionRuns400TextResponse string
 
-func (response ListSourceNodes400TextResponse) VisitListSourceNodesResponse(w http.ResponseWriter) error {
+func (response ListSourceIngestionRuns400TextResponse) VisitListSourceIngestionRunsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(400)
 
@@ -1082,9 +2864,9 @@ func (response ListSourceNodes400TextResponse) VisitListSourceNodesResponse(w ht
 	return err
 }
 
-type ListSourceNodes404TextResponse string
+type ListSourceIngestionRuns404TextResponse string
 
-func (response ListSourceNodes404TextResponse) VisitListSourceNodesResponse(w http.ResponseWriter) error {
+func (response ListSourceIngestionRuns404TextResponse) VisitListSourceIngestionRunsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(404)
 
@@ -1164,9 +2946,68 @@ func (response StopSource404TextResponse) VisitStopSourceResponse(w http.Respons
 	return err
 }
 
+type SyncSourceRequestObject struct {
+	Id int `json:"id"`
+}
+
+type SyncSourceResponseObject interface {
+	VisitSyncSourceResponse(w http.ResponseWriter) error
+}
+
+type SyncSource202JSONResponse IngestionRunEntry
+
+func (response SyncSource202JSONResponse) VisitSyncSourceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SyncSource400TextResponse string
+
+func (response SyncSource400TextResponse) VisitSyncSourceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(400)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type SyncSource404TextResponse string
+
+func (response SyncSource404TextResponse) VisitSyncSourceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(404)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
+type SyncSource409TextResponse string
+
+func (response SyncSource409TextResponse) VisitSyncSourceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(409)
+
+	_, err := w.Write([]byte(response))
+	return err
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
 
+	// (GET /admin/keys)
+	ListApiKeys(ctx context.Context, request ListApiKeysRequestObject) (ListApiKeysResponseObject, error)
+
+	// (POST /admin/keys)
+	CreateApiKey(ctx context.Context, request CreateApiKeyRequestObject) (CreateApiKeyResponseObject, error)
+
+	// (DELETE /admin/keys/{id})
+	RevokeApiKey(ctx context.Context, request RevokeApiKeyRequestObject) (RevokeApiKeyResponseObject, error)
+
+	// (POST /admin/keys/{id}/rotate)
+	RotateApiKey(ctx context.Context, request RotateApiKeyRequestObject) (RotateApiKeyResponseObject, error)
+
 	// (GET /allowlist)
 	ListAllAllowlists(ctx context.Context, request ListAllAllowlistsRequestObject) (ListAllAllowlistsResponseObject, error)
 
@@ -1185,23 +3026,59 @@ type StrictServerInterface interface {
 	// (DELETE /allowlist/{id}/entry/{entryId})
 	RemoveFromAllowlist(ctx context.Context, request RemoveFromAllowlistRequestObject) (RemoveFromAllowlistResponseObject, error)
 
+	// (GET /audit)
+	ListAuditLog(ctx context.Context, request ListAuditLogRequestObject) (ListAuditLogResponseObject, error)
+
+	// (GET /healthz)
+	GetHealth(ctx context.Context, request GetHealthRequestObject) (GetHealthResponseObject, error)
+
 	// (GET /nodes)
 	ListAggregatedNodes(ctx context.Context, request ListAggregatedNodesRequestObject) (ListAggregatedNodesResponseObject, error)
 
+	// (GET /nodes/export)
+	ExportNodes(ctx context.Context, request ExportNodesRequestObject) (ExportNodesResponseObject, error)
+
+	// (POST /nodes/lookup)
+	LookupNodes(ctx context.Context, request LookupNodesRequestObject) (LookupNodesResponseObject, error)
+
+	// (GET /nodes/{ip})
+	GetNode(ctx context.Context, request GetNodeRequestObject) (GetNodeResponseObject, error)
+
+	// (GET /nodes/{ip}/history)
+	ListNodeHistory(ctx context.Context, request ListNodeHistoryRequestObject) (ListNodeHistoryResponseObject, error)
+
+	// (GET /readyz)
+	GetReadiness(ctx context.Context, request GetReadinessRequestObject) (GetReadinessResponseObject, error)
+
+	// (GET /runs/{id})
+	GetIngestionRun(ctx context.Context, request GetIngestionRunRequestObject) (GetIngestionRunResponseObject, error)
+
 	// (GET /sources)
 	ListSources(ctx context.Context, request ListSourcesRequestObject) (ListSourcesResponseObject, error)
 
 	// (POST /sources)
 	CreateSource(ctx context.Context, request CreateSourceRequestObject) (CreateSourceResponseObject, error)
 
+	// (DELETE /sources/{id})
+	DeleteSource(ctx context.Context, request DeleteSourceRequestObject) (DeleteSourceResponseObject, error)
+
 	// (GET /sources/{id})
 	ListSourceNodes(ctx context.Context, request ListSourceNodesRequestObject) (ListSourceNodesResponseObject, error)
 
+	// (PATCH /sources/{id})
+	UpdateSource(ctx context.Context, request UpdateSourceRequestObject) (UpdateSourceResponseObject, error)
+
+	// (GET /sources/{id}/runs)
+	ListSourceIngestionRuns(ctx context.Context, request ListSourceIngestionRunsRequestObject) (ListSourceIngestionRunsResponseObject, error)
+
 	// (POST /sources/{id}/start)
 	StartSource(ctx context.Context, request StartSourceRequestObject) (StartSourceResponseObject, error)
 
 	// (POST /sources/{id}/stop)
 	StopSource(ctx context.Context, request StopSourceRequestObject) (StopSourceResponseObject, error)
+
+	// (POST /sources/{id}/sync)
+	SyncSource(ctx context.Context, request SyncSourceRequestObject) (SyncSourceResponseObject, error)
 }
 
 type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
@@ -1223,14 +3100,123 @@ func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareF
 	}}
 }
 
-func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
-}
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// ListApiKeys operation middleware
+func (sh *strictHandler) ListApiKeys(w http.ResponseWriter, r *http.Request, params ListApiKeysParams) {
+	var request ListApiKeysRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListApiKeys(ctx, request.(ListApiKeysRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListApiKeys")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListApiKeysResponseObject); ok {
+		if err := validResponse.VisitListApiKeysResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateApiKey operation middleware
+func (sh *strictHandler) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	var request CreateApiKeyRequestObject
+
+	var body CreateApiKeyJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateApiKey(ctx, request.(CreateApiKeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateApiKey")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateApiKeyResponseObject); ok {
+		if err := validResponse.VisitCreateApiKeyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RevokeApiKey operation middleware
+func (sh *strictHandler) RevokeApiKey(w http.ResponseWriter, r *http.Request, id int) {
+	var request RevokeApiKeyRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RevokeApiKey(ctx, request.(RevokeApiKeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RevokeApiKey")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RevokeApiKeyResponseObject); ok {
+		if err := validResponse.VisitRevokeApiKeyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RotateApiKey operation middleware
+func (sh *strictHandler) RotateApiKey(w http.ResponseWriter, r *http.Request, id int) {
+	var request RotateApiKeyRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RotateApiKey(ctx, request.(RotateApiKeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RotateApiKey")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
 
-type strictHandler struct {
-	ssi         StrictServerInterface
-	middlewares []StrictMiddlewareFunc
-	options     StrictHTTPServerOptions
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RotateApiKeyResponseObject); ok {
+		if err := validResponse.VisitRotateApiKeyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
 // ListAllAllowlists operation middleware
@@ -1402,6 +3388,56 @@ func (sh *strictHandler) RemoveFromAllowlist(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+// ListAuditLog operation middleware
+func (sh *strictHandler) ListAuditLog(w http.ResponseWriter, r *http.Request, params ListAuditLogParams) {
+	var request ListAuditLogRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListAuditLog(ctx, request.(ListAuditLogRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListAuditLog")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListAuditLogResponseObject); ok {
+		if err := validResponse.VisitListAuditLogResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetHealth operation middleware
+func (sh *strictHandler) GetHealth(w http.ResponseWriter, r *http.Request) {
+	var request GetHealthRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetHealth(ctx, request.(GetHealthRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetHealth")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetHealthResponseObject); ok {
+		if err := validResponse.VisitGetHealthResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // ListAggregatedNodes operation middleware
 func (sh *strictHandler) ListAggregatedNodes(w http.ResponseWriter, r *http.Request, params ListAggregatedNodesParams) {
 	var request ListAggregatedNodesRequestObject
@@ -1428,6 +3464,169 @@ func (sh *strictHandler) ListAggregatedNodes(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+// ExportNodes operation middleware
+func (sh *strictHandler) ExportNodes(w http.ResponseWriter, r *http.Request, params ExportNodesParams) {
+	var request ExportNodesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ExportNodes(ctx, request.(ExportNodesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExportNodes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExportNodesResponseObject); ok {
+		if err := validResponse.VisitExportNodesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// LookupNodes operation middleware
+func (sh *strictHandler) LookupNodes(w http.ResponseWriter, r *http.Request, params LookupNodesParams) {
+	var request LookupNodesRequestObject
+
+	request.Params = params
+
+	var body LookupNodesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.LookupNodes(ctx, request.(LookupNodesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "LookupNodes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(LookupNodesResponseObject); ok {
+		if err := validResponse.VisitLookupNodesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetNode operation middleware
+func (sh *strictHandler) GetNode(w http.ResponseWriter, r *http.Request, ip string, params GetNodeParams) {
+	var request GetNodeRequestObject
+
+	request.Ip = ip
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetNode(ctx, request.(GetNodeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetNode")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetNodeResponseObject); ok {
+		if err := validResponse.VisitGetNodeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListNodeHistory operation middleware
+func (sh *strictHandler) ListNodeHistory(w http.ResponseWriter, r *http.Request, ip string, params ListNodeHistoryParams) {
+	var request ListNodeHistoryRequestObject
+
+	request.Ip = ip
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListNodeHistory(ctx, request.(ListNodeHistoryRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListNodeHistory")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListNodeHistoryResponseObject); ok {
+		if err := validResponse.VisitListNodeHistoryResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetReadiness operation middleware
+func (sh *strictHandler) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	var request GetReadinessRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetReadiness(ctx, request.(GetReadinessRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetReadiness")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetReadinessResponseObject); ok {
+		if err := validResponse.VisitGetReadinessResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetIngestionRun operation middleware
+func (sh *strictHandler) GetIngestionRun(w http.ResponseWriter, r *http.Request, id int64) {
+	var request GetIngestionRunRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetIngestionRun(ctx, request.(GetIngestionRunRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetIngestionRun")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetIngestionRunResponseObject); ok {
+		if err := validResponse.VisitGetIngestionRunResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // ListSources operation middleware
 func (sh *strictHandler) ListSources(w http.ResponseWriter, r *http.Request, params ListSourcesParams) {
 	var request ListSourcesRequestObject
@@ -1485,6 +3684,32 @@ func (sh *strictHandler) CreateSource(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// DeleteSource operation middleware
+func (sh *strictHandler) DeleteSource(w http.ResponseWriter, r *http.Request, id int) {
+	var request DeleteSourceRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteSource(ctx, request.(DeleteSourceRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteSource")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteSourceResponseObject); ok {
+		if err := validResponse.VisitDeleteSourceResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // ListSourceNodes operation middleware
 func (sh *strictHandler) ListSourceNodes(w http.ResponseWriter, r *http.Request, id int, params ListSourceNodesParams) {
 	var request ListSourceNodesRequestObject
@@ -1512,6 +3737,66 @@ func (sh *strictHandler) ListSourceNodes(w http.ResponseWriter, r *http.Request,
 	}
 }
 
+// UpdateSource operation middleware
+func (sh *strictHandler) UpdateSource(w http.ResponseWriter, r *http.Request, id int) {
+	var request UpdateSourceRequestObject
+
+	request.Id = id
+
+	var body UpdateSourceJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateSource(ctx, request.(UpdateSourceRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateSource")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateSourceResponseObject); ok {
+		if err := validResponse.VisitUpdateSourceResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListSourceIngestionRuns operation middleware
+func (sh *strictHandler) ListSourceIngestionRuns(w http.ResponseWriter, r *http.Request, id int, params ListSourceIngestionRunsParams) {
+	var request ListSourceIngestionRunsRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListSourceIngestionRuns(ctx, request.(ListSourceIngestionRunsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListSourceIngestionRuns")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListSourceIngestionRunsResponseObject); ok {
+		if err := validResponse.VisitListSourceIngestionRunsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // StartSource operation middleware
 func (sh *strictHandler) StartSource(w http.ResponseWriter, r *http.Request, id int) {
 	var request StartSourceRequestObject
@@ -1564,27 +3849,117 @@ func (sh *strictHandler) StopSource(w http.ResponseWriter, r *http.Request, id i
 	}
 }
 
+// SyncSource operation middleware
+func (sh *strictHandler) SyncSource(w http.ResponseWriter, r *http.Request, id int) {
+	var request SyncSourceRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SyncSource(ctx, request.(SyncSourceRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SyncSource")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SyncSourceResponseObject); ok {
+		if err := validResponse.VisitSyncSourceResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xZW2/bNhT+KwG3RzVy5zzpzbsBwbqtmPswoAgCVjy22VEkSx5lNQz994HU/WbLXuy5",
-	"WZ+sSDzX75yPh8yOxCrRSoJES6IdsfEGEuofF4wthFB/C27xJ4lmey91iu4LZYwjV5KKt0ZpMMjBkmhF",
-	"hYWA6MarHYk5M+4XtxpIRCwaLtckywJi4FPKDTASvc9XPQTlKvXhI8RIsoC0HTjSNmcNy1wirME4pZIm",
-	"cNgnzkix9LBj9wjJkc7RUsHjmJsjqQtG4hpy36sI2raGovnBAEWoYjoF6GlJHc1n7sFSpSaGk4ttxIeA",
-	"aDBcscFPqRET3c7XVsqGovhNMTipUvUjZSNoW5+TfBlC4h++NbAiEfkmrJs3LDo3dD408uhUFDqpMXTb",
-	"L5TCdm1pLLKm1uPiE9TiI3yGOHUCe8IcbYYnMLYtOlb4taJaKuh6MBTiW7rmkiKwAdIR4vcVid7vz3yl",
-	"4FdAyihSkgVHpcnLTMW54+UhmL3uftgPzcArv4/k+NRYNVy8G2ofE2WaXflBKQFUeocVUjEB0sJAKdBQ",
-	"uxfHdjdeIYS1g8+BXrc9rzDgY3hpT8inE9EYvUwgqFM2F5NK6R4Hq3945zmG6uoRpbM39QJqUmHpVD+3",
-	"joYhTg3H7dIhVkwqmv8CPtFckohsgDIwpeGI/Plqofkrt6IGNJfInEIuV8qHwlG4bw6iDeDNsjB0847+",
-	"BRuVwM1C84afEZndvr6duYQoDZJqTiIyv53dzlyYFDfet7Aabdxfa/A/DnDqwr5nJCJvuMWFEBVdWi9v",
-	"aAIIxvoG8XF9SsFs67DoCn2UeekOjgjDgoInHIcEaxQfHIxWK2nzDH83m3keVRJB5kOP1oLHPojwo82L",
-	"oVY4qaG7+4MDg4GNDdd5jROX27ueaYTPGGpBecdoN/wBda6l6drltB45iWtYrewAMp2pk+TFDRa/V2z7",
-	"bAkZnG2zdiuhSSHrgfL62Xy4HiyyoNEz4Y6zzJM4CEDoI/Sjf++9f1Mi1ErRXS591kicurtLFOkAKTii",
-	"qVvb8227avb3eT/bIZS71j6yqovF7Vv/ki1OmCb9eba/QZ+/ar9grMdYbsHYO3Vukhu5q/lPaS4vohdT",
-	"NGO9HO78z/1+Jv0DEvUEPxuVtEvhK5tO7rBgUEuR/BOIWSqWJ36ciddrA+vyKDl1cKwmDUYmxdOR5/IJ",
-	"zOAAWZ0dxkS/+Jm1cSC+7IjkaqHo8cZ922hhLIs1/4+TROvUfllcqivJA4eI3MWzniB6d9MX3lyvAoVG",
-	"g1SHhwNdcgx1fuWv69jKW213nmNRs4pCi9R49y84ly+dzQZvvKBJbLxfQ4tKXzzRSr/4PPurU/NU5tPf",
-	"7ZINoo7CUKiYio2yGM3n8zlxmSo0lP+yzOcPx27dIbL5sjSXPWT/BAAA//8Fh7cDtx8AAA==",
+	"H4sIAAAAAAAC/+w9a28cu3V/hZgGaBOMV3LsFqj6SffGTozc5BqWixvUNRRq5uwOoxlyLsnRamPsfy/O",
+	"ITmPHc6+rLXlWp8k7fJxeN4vUp+STFW1kiCtSS4+JSYroOL062WeX5alWpbC2FfS6tUbWTcWv+F5LqxQ",
+	"kpdvtapBWwEmuZjz0kCa1L2PPiWZyDX+tKsakovEWC3kIlmv00TDr43QkCcXH9yoj2kYpW7+AZlN1mky",
+	"BODAvUXe21lICwvQuKjkFeyGSeSJH7obsDcWqgOB42GB6ykwJ1CXTpwrBj4tkQ73ip6mFn+G1TE4zjRw",
+	"C/k1tweAmiYlN/a6MdMTJ2iE28Nc3ONXOZhMixoBTS6S9wWwudDGsqzgmmcWtGFqzmwB7BZWKcPdmFXM",
+	"QlniJ4bxmmubpOM9NNyp22nYTKZqz2EWKvrlNxrmyUXyL2edNJ15UTpzyL3CSTjbL8e15qstTNeetN0v",
+	"7SN7mopuo4tPCcimwjWlysFcaOB5nxXGHyy1sLivUY3OuhnhT55XQiZpgqhr/+BNLvxSHyOIvMSvj2Er",
+	"nlmlx0T+K68gEPXy7RskI7MFt6ziOdCnWcHlAmJEpSWvb2HlBW648pt8n3VTVgljhFwwJTMIvMWEYTmU",
+	"YCFP0giz87kFPY0AqxtII9yswaGe0fwoFMsCJBOWLXkEgo4xbmCuNHwGBG6BnSB4/oyBMKUIVJY1Wk/L",
+	"GgLJHUibJPul4G7bXElI2VxpBve8qktgDuyZg4cpzToNCMiNMw2VuoO47P/aQKuVxzj527N3bsSzjmX8",
+	"nL1ZMeB1qPoj37tvdqgXlLF3fsZ7nBBVKk6iBufrY3dz0yGQQzpFdc8Iip4Gcp/2lc3AKBFJ8JNaoHxG",
+	"NcmPRMrW6h7jiuxn9ictvoeAdOwDbv+55qQS8o2b9nyHbfFmxW83fcQrotbxHp+GHKQVvNx5FLfTj70J",
+	"6zSZK11xu9/U125sO+takaiaQ2b/7KdsdTpACxWX1UaXezKVG9suNk2AfNMdK8uf58nFh334wk1apweR",
+	"DIUuquxaW6iYAZkzIUmz/e3ZZS2e/RlWrACeg07RAGRcMqksuwGmwWoBd5AzvuDkJ2xHz1DoAzo+rtPk",
+	"jVyAQYDeNfIYP+JmZcFcz8FmBUQU+g/4NWrxOUDONPCc8UwrYxjcgV4xbi1UtU06rkyEtP/xMmrmQeuY",
+	"z/JLsSKkiXAUNueihHyMlTSZCylMMW0NC2vra2O5bcx4oyv6PNgk9LCZBlMraYDNtaroYzzohvGWqhuH",
+	"5lRDBuJuwpdxFmsPbJDXec3zPIb3v+KXzlzyugauoeWturkpCQfMSF6bQtktyzszvn2DXJh2jxYNe+7i",
+	"jeU0QbRamuuaaxMD4jUyFUirRYDFjWRCWsW4ZKJmPM81GBPfHRfXgNKw3/JL0MDMrahryNkNZLwx5Iqs",
+	"WC5yEs5ClTnj7I6XIt+1fcxH6X9tud6CmY5Ltymtvnw7/sW5VovFAvQhk9/7Kes0uQNtog4jajTv0Pox",
+	"7E4YcVNC587rRrIghaTWkMEMOpdLrnMnMGFcx0ScSVj2WWmngMRctL6/FVDQInKDGWPWI4LMEQ5qkDlK",
+	"fiOtKIkHaRJoVovs1rRna+oUf0qnJUQJiAs31g1ywKbMBoyoxmaKzFxw+/xeCLpbCU/TZJljuEY6BxlP",
+	"ixqx0RD1/GJUHh2r4rLh5ZA8Tpd5nDFbaNUsCvqW1yJlyEV5U0KPqO5M/2qYM9Esw3CTa9XIvHcstxd5",
+	"Un6FKNyogo4xWZTEuDYA0ZAHJONyFdiYxjINtdLujMBQMcZMi6hRGccTSiXfsWPYr+T7bucm7O/VIrZ6",
+	"jufuRIk/TtrHV/8kHQgxUcHt/iSMVfqorBfcgbT9CCdYmCRNevYmyhdT+nQbgXZFyVtVdUzVdOjrKx13",
+	"rN3BHmLvJ6Vum/qYAMHvPeSN0ZEqfh/imvPz8/NDAp12g+2wvwPTlEcCHxcjYaKG+pcCbOEzOJ3NZcIw",
+	"QrO05Qo1MV8sNCw4ShZKlU/78LouV6iFcXY/fvbb3yhVApfBJ9pHzryETUqUP8cU9vpiehjySDrhHrIm",
+	"ZHQO4+SBbd/B5n2+DrPSTQhiR3zLF0JSFDauPewViLUL/AUsz7nlh4ZjNGfvdMAQyl16k9aOx1rdwY8J",
+	"P7/wqQfB7gMceZiofown7iB8iAPHA+tHeO4xoA9x/Bb0A3NbjTYqrvwLbq4rn2Ufq2arLC8jYWpT3YDG",
+	"kJ1w4WJgYVjN+4njnv6jda4z1Ui7e7VBKgOXTJmS5YppsI2WwfFtXeTdUYo/fjhO79BbFenQE36EPNYz",
+	"ig/AW1Hf8pGeegDnQxx+0zl4hOc+JMzYcuR3wHMhwZgu1D4Q4htuYCzF6rYLSMMoxqVZgoY8ZcoWoJfC",
+	"JXYY5RwxNHc5RbYUtoiFY3BfUxbp2iHhemuOpBILVxVq0ySklAzoO9AUVt80orQuuWumMmc8X8V1oQch",
+	"F9qutjvLFG520Pgz1lxj3W8VQvoknd7k4HP2cC4M43a3UnQnTTuKTmM7piUdN/7AjcguG1scyEY1N2ap",
+	"9ERpwoDer+jVjky7FaeB/XFY4dkK7kaKGqQlHvVGKRROsXbbJWBYo8sZeyUzvaot5IxTItsyLnMmcWJr",
+	"wVKMm6Cq7Yo5IJnLCFOKqkpG1QDE8jX3aN6tIzqqUBGda9DXVt3G8iR0Mm7Y33G40uKfxFgX7Aeaxf63",
+	"OT9/kdFc+hX+HpNSV03ZgtQIkYdgvLq3mrdo9eulDGaLGaIq1HPcFyywALtZtcWBcfF+PckIRzUMKWko",
+	"/LqDa5/2M1tdmeD/+Rx3we8gaAKBqSmtllEN9BXriOgWbZRBp5Wc5/nC9U+EOanL23MNGywf1XZb26wm",
+	"6lLvW/PRVkUqRem9DHm5RbFHf8qy0hVRlGRdFjeeS9we49OQsEQ849hDDNobnOFB8UZONZZkn86WHtI/",
+	"xqntjPLfMTG2XS2gRxmPDasUqzD/akUFpuU/KhQIQxWWFkqyjj7PTvYT5zCl/Uduhi+ixE6wpeYc0ulR",
+	"8xovSB+SOuk3oQ1K1q1QjeRkzPMjTuinYbp6QFQdTJue161QD+n2J7V0xltlTYXsS85Kq9g6u8JMoZoS",
+	"q2O+GDdjf4A5b0prsMqdmbtept/9VQoJxOpKP4N7YZ/5BB59+A8zsOsdumOq4VBNWTZVxNT8W2bufsv+",
+	"B7Ri6G+gjOZwH+q+bhYV+kLWcFDnq4QUFR7vPKY0nWGY2vPqVtTOULi6g1qGXQPeo9qp5raILImo+y17",
+	"y22BmB8CCsFo/WamoeQr8+F3H2eI/Ot2wIfffYw2Fow457/r/JiulkrI/qfPn/pc4n7k2FtAgdbCrq4Q",
+	"CocsTvm6MRMEh8SgzE50l8zYK54VrO1VY6Voi5CZqqky6dWX+S/GuxZNVLW3Ui1laNIMtXTngnL28vw5",
+	"OZWcBgSz0i3cDnsxY79oYcGNzish3QjDeGkUW2gu3TxqIXGTvWQYrCGGXrpZkiYCz+3lLJAiac/csbRH",
+	"2RoxKuRcEfaFLfE7ZMwCLLvymGbv+S0UqgJ2WYuepr1IzmfPZ+e+kVLyWiQXyYvZ+ew8cWJJxDmjE51h",
+	"Yy/+uYCIgv2JcM7LMviQJmVCZmVDWsY3TDMlwczY+9AWaw2UczKMAxdm1u88fJP71V1K1xBgmldgyRP+",
+	"sAnIj5SDIl2tpBWyQUVO6QMKEbWqsBW06ZqVagSuaUvBgQK/NqBXHQFcj26IG6OcPu2jajDBgJhCLSe2",
+	"KEUlbGyLzg5/TJPQikOk+P35ufeZrS84YjlIZHTYM7I97Z2N5GLPJMsgd07s9XK0jYV7e1aXXGxssImU",
+	"dV/eiVpB0j/0G8U/4sksXyBBk/BRmtTKRFjtL0Ja41sqPLP1eMps5DBDyjTgbcxc/cbNro3iB5WvNg59",
+	"PG7HvaHroVNldQPrEXGfPzAAj4a067SvVM4+iXzt6FyCjaS83pH6MP32+zTIq2ENaXNhKRhqVXgIWiRT",
+	"ckRyt2CP5AO0vxxDkHwmqnD2y68jQ9uUJUqN2GxThzwgOSgqctBaPUXu/5B1tyutCLXPtLLckfoRADil",
+	"aSJ8Rxa+8hpIQ13yDKo2a9UadISEhjo3wOknVFhH6qh3hK4phn3keuJrMT/xXdsRsdtzaTsoWg8y5JNu",
+	"AGTv9krEOSnLttD+5KKc3kXZaGo4oSnbuBA3ZLTw3RZvxUlU8Fc69ppyQ8qug+eEnsjwnswXdka+CvXc",
+	"7cVJ8g10xU6H5A/0udkwSu38Np4bEdlNJAT8FIj8rfoee+P2SCckis4H80cGtD6DUKnYw0QMWuo780BX",
+	"GUKmag9W8GajkwQBJvlM5XlERxhdTR+XudePnul2quNHxXNT5uEyzw0VKpAW7uLHZiPnkG0u8/y9OrWV",
+	"mHja4avaCceo6/W3rg2ntM/ZJ/rxZlco3NavPc+01blpnnGTXmtVDRnnyfJ8WS2Q7gtA0AbTtiQCjWef",
+	"Y4xhk4udARKOYRoypXO6RIg01apkdcklsKqxxG8mRT+XejaEjrAiWT1c6ie12BUn/YxBMkYj7bZ0d55q",
+	"dsK0EbmvREZDIn+p/YCQaLypaz4RhuFcHysNyLCxqe7fck/31XWxW/oHwBY2ZSJPWWMaXpYrlqnqRshQ",
+	"z9iAaxvoxEWfhTRyjsI9EYa/6/adCl/rnoDBCJkN8dZelsu5hWd+6mfD1L5ZsR0c1wjw+eA8Bf2fHfT3",
+	"OtxPGTL2nqyZyioVwEtb/HOLyrwDCcawrIDsNnX9MICenmGlkgv8iTT2LZPCMI63TK1iBZd52ZoCM9Kf",
+	"fwT7J9p8d6hwLDJ6Z3bn9IemK9VbrQSFSFLlkeCou7tE/goODPfvoiaiHU83tXdZiteiJNWiXGKVpL4H",
+	"B9fQ5lj7XlJUoNrUSJ4cZsxfC1s6IFyToYdgINS7dhfyDnRU2Np+ie9MtYz2uMSKOt1w8E2iiGZWcZsV",
+	"oaFlTuxgKAHvkuxUHjKsfz0iDlH4bgv+v4iy69/DO52u6z39NdB1+Hlf6s/gvlZ62kW8shp41b6KsXlR",
+	"EbOf85JbSnw6kZwLDUtelo5ItVb3Agy9EZIpaZpqnC95RSDspQ7svWUUGRimJLUN1aBZKSSk2MFFWyJd",
+	"Stee4foU+j19Dqq2zZ5O0fYZ+uvDMe5p2986cuSue8xB1esdc3+5DjKCJdIlFtMx35GiO0zQ7p/JfCxs",
+	"kX4klBvE+z7j9g5zv6p0lnRfGZefqEGgF2LwnYasCMHmAl2UQUcdcU/vxvFQip1I8AxdadKzyq/nJWbG",
+	"3nmV78rw/dImMKVz1868co+Q9C+VbVh/OsuT1Y8Kw8Nn+jav6u+V4jtBUnp07f7hU9IPIGifRL2eNIJ/",
+	"BN916IyezAcGpRMs9z6G61mJ+feIiifW/0IO10P6WadKd+5gz32SnN2rEqNco3TsFktv1vvkEtsjfBzK",
+	"yVnh7o7uyCsOnaz+a18oQaPHuYC3Jidlqsy3Zxp7V1j3TzYiMB72gC2CLQjU1uTftvzZFlF6yg49RMA0",
+	"vK389QzGo5JIhHI1napqrymPclXtPaf+jVeNAkjJKtcIhzdku7J37D6wu+wazWS1eycn5JDNe9iOsP9+",
+	"/uKkO+yXUtON7Lpf45F1oZY+V9g+39i+yoao1o2Mobb/LMYpsRt5fuPR2tHBw+UDuW2fAju2YrhJj8OL",
+	"hbtfBCRp7r2btqNVxY9s88wic1fWN8M6NC5R43nV5mefuhlParoGD06c0Grtyf97tDG68Yheejp3Gyf1",
+	"34w+aU/j6ALfF+5WeVgq4uz/PHb25q3/y/audKRJvSQHgcG9MNYke7BPpOO645+ejjqoi9IDyKk6RmAK",
+	"a3qJJ1uA0MEtn2ir7DHZt9rZsheO0326FKNlOP/Qddcz1FkwT4Ct7YoOwXvl5p5qUt0NT8/aT8Wob9S5",
+	"G4vGA3WEYs0ylq3n6E32svRzAWVuhvw0Y5dkihtdpsw5j+En87e/mdL9J0NYxW8HD1fkDeAMp2nh3va8",
+	"WGM1F4vCMr7kq9Rbff/qMTGUN/eRuePLS/0L9idyASbu8J8gm/1lXYCX370DQTHyjnBn+O8TcMJQVvbo",
+	"SXSk60ezT4HPye3KU/LghPZlJEj0ltDRN25PZgQnbt4StGbTgfq1gQYMOlBNTbbLrGSGpTT30o2SZJBQ",
+	"0ARKWvu6EHV4pq7doxRzqr5xRm89sRLmFpuKNdTgOtTCO0ObiuIKYfpeoowI/6j6G2GfK6vqAe+QrxJ4",
+	"hctVpXTwyN19imHY1N2p6LJl0ZsVuNH3yxArmX0z+sQ/FjHInW8EaAVwAhRVSPjfHSkGdLL3L06M0xp5",
+	"VGPM2FvlGantgKH/GOO9bP8/UNB3x48pOqcSvXOhllyQZtp47lKYbjUhjfVgelw6jqZnX8eu99VKZlP8",
+	"+fv/x0WAB/V+3w9IgWqwhvzzHV2ajyUyJzb0gBb9B6+Ls7NSZbwslLEXL168eJH0Vgj/KNDVGtdp+3fX",
+	"adH7MGzXH0dA9T7wFan1x/X/DQB8OPpU7XkAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,398 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is prepended to every environment variable override. The rest of
+// the name is the upper cased yaml path of the setting joined by underscores,
+// for example ingest.gc.batch_size is overridden by PROPHET_INGEST_GC_BATCH_SIZE.
+const EnvPrefix = "PROPHET"
+
+// PathEnv names the environment variable that points to the config file when
+// the -config flag is not given.
+const PathEnv = "PROPHET_CONFIG"
+
+var (
+	ErrInvalidConfig = errors.New("Invalid configuration")
+)
+
+type Config struct {
+	Database DatabaseConfig `yaml:"database"`
+	Log      LogConfig      `yaml:"log"`
+	Web      WebConfig      `yaml:"web"`
+	Ingest   IngestConfig   `yaml:"ingest"`
+	Mock     MockConfig     `yaml:"mock"`
+	Secrets  SecretsConfig  `yaml:"secrets"`
+}
+
+type DatabaseConfig struct {
+	Dsn string `yaml:"dsn"`
+
+	// MaxConns and MinConns bound the number of open connections in the pool.
+	MaxConns int `yaml:"max_conns"`
+	MinConns int `yaml:"min_conns"`
+
+	// MaxConnLifetime and MaxConnIdleTime control when pooled connections are
+	// closed and replaced.
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time"`
+
+	// HealthCheckPeriod is how often idle connections are checked and broken
+	// ones are dropped from the pool.
+	HealthCheckPeriod time.Duration `yaml:"health_check_period"`
+
+	// QueryExecMode is one of cache_statement, cache_describe, describe_exec,
+	// exec or simple_protocol. Anything other than the caching modes is only
+	// needed behind a connection pooler that does not support prepared statements.
+	QueryExecMode string `yaml:"query_exec_mode"`
+
+	// StatementCacheCapacity is the number of prepared statements (or
+	// descriptions) cached per connection.
+	StatementCacheCapacity int `yaml:"statement_cache_capacity"`
+}
+
+type LogConfig struct {
+	// Level is one of debug, info, warn or error.
+	Level string `yaml:"level"`
+
+	// Format is either text or json.
+	Format string `yaml:"format"`
+}
+
+type WebConfig struct {
+	ListenAddress string `yaml:"listen_address"`
+
+	// ShutdownTimeout is how long in-flight requests may take to finish once
+	// a shutdown signal is received.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type IngestConfig struct {
+	// ListenAddress is where the ingester serves its health endpoints.
+	ListenAddress string `yaml:"listen_address"`
+
+	// StallAfter is how long the run loop may go without starting an
+	// iteration before the health check reports it as stalled.
+	StallAfter time.Duration `yaml:"stall_after"`
+
+	// PollInterval is how often due sources are looked for while source
+	// notifications can not be received, and how often the idle run loop
+	// checks in with the health check.
+	PollInterval time.Duration `yaml:"poll_interval"`
+
+	Concurrency   int           `yaml:"concurrency"`
+	SourceTimeout time.Duration `yaml:"source_timeout"`
+	InstanceId    string        `yaml:"instance_id"`
+	LeaseDuration time.Duration `yaml:"lease_duration"`
+
+	// ShutdownTimeout is how long in-flight ingestions may take to finish once
+	// a shutdown signal is received before they are cancelled and rolled back.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	Retry          RetryConfig          `yaml:"retry"`
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker"`
+	Gc             GcConfig             `yaml:"gc"`
+}
+
+// RetryConfig controls how often a fetch that failed with a network error or
+// a 5xx/429 response is retried within a single ingestion.
+type RetryConfig struct {
+	// MaxAttempts includes the first attempt, 1 disables retries.
+	MaxAttempts int `yaml:"max_attempts"`
+
+	// InitialBackoff is doubled after every attempt up to MaxBackoff, each
+	// delay is jittered between half and all of its value.
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+}
+
+// CircuitBreakerConfig controls when a failing source is paused.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed ingestions after
+	// which the source is paused.
+	FailureThreshold int `yaml:"failure_threshold"`
+
+	// Cooldown is how long a paused source is skipped before it is tried
+	// again, unless it is started again before that.
+	Cooldown time.Duration `yaml:"cooldown"`
+}
+
+type GcConfig struct {
+	Interval    time.Duration `yaml:"interval"`
+	GracePeriod time.Duration `yaml:"grace_period"`
+	BatchSize   int           `yaml:"batch_size"`
+
+	// RunRetention is how long finished ingestion runs are kept.
+	RunRetention time.Duration `yaml:"run_retention"`
+}
+
+type MockConfig struct {
+	ListenAddress   string        `yaml:"listen_address"`
+	StaticDir       string        `yaml:"static_dir"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type SecretsConfig struct {
+	// Key is the base64 encoded 32 byte key that source credentials are
+	// encrypted with. Sources can only be given credentials when it is set, and
+	// it must not change once they have been.
+	Key string `yaml:"key"`
+}
+
+// Default returns the configuration used for local development, every value
+// can be replaced by the config file or the environment.
+func Default() Config {
+	return Config{
+		Database: DatabaseConfig{
+			Dsn:                    "host=localhost port=5432 user=prophet-th password=prophet-th dbname=prophet-th sslmode=disable",
+			MaxConns:               10,
+			MinConns:               0,
+			MaxConnLifetime:        time.Hour,
+			MaxConnIdleTime:        30 * time.Minute,
+			HealthCheckPeriod:      time.Minute,
+			QueryExecMode:          "cache_statement",
+			StatementCacheCapacity: 512,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Web: WebConfig{
+			ListenAddress:   "127.0.0.1:3333",
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Ingest: IngestConfig{
+			ListenAddress:   "127.0.0.1:3335",
+			StallAfter:      time.Minute,
+			PollInterval:    10 * time.Second,
+			Concurrency:     4,
+			SourceTimeout:   5 * time.Minute,
+			LeaseDuration:   10 * time.Minute,
+			ShutdownTimeout: time.Minute,
+			Retry: RetryConfig{
+				MaxAttempts:    3,
+				InitialBackoff: time.Second,
+				MaxBackoff:     30 * time.Second,
+			},
+			CircuitBreaker: CircuitBreakerConfig{
+				FailureThreshold: 5,
+				Cooldown:         time.Hour,
+			},
+			Gc: GcConfig{
+				Interval:     15 * time.Minute,
+				GracePeriod:  24 * time.Hour,
+				BatchSize:    5000,
+				RunRetention: 7 * 24 * time.Hour,
+			},
+		},
+		Mock: MockConfig{
+			ListenAddress:   "127.0.0.1:3334",
+			StaticDir:       "./static",
+			ShutdownTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the yaml file at path (if
+// path is not empty) and finally the environment, then validates the result.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&cfg)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return Config{}, fmt.Errorf("%w: %s: %w", ErrInvalidConfig, path, err)
+		}
+	}
+
+	err := applyEnv(reflect.ValueOf(&cfg).Elem(), EnvPrefix)
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// applyEnv walks the struct and replaces any field that has a matching
+// environment variable set.
+func applyEnv(value reflect.Value, prefix string) error {
+	var errs []error
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		tag, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("yaml"), ",")
+		name := prefix + "_" + strings.ToUpper(tag)
+
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field, name); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case field.Type() == reflect.TypeOf(time.Duration(0)):
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid duration %q", name, raw))
+				continue
+			}
+			field.SetInt(int64(d))
+		case field.Kind() == reflect.Int:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid integer %q", name, raw))
+				continue
+			}
+			field.SetInt(int64(n))
+		case field.Kind() == reflect.Bool:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid boolean %q", name, raw))
+				continue
+			}
+			field.SetBool(b)
+		case field.Kind() == reflect.String:
+			field.SetString(raw)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid setting at once so they can all be fixed
+// before the next start.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Database.Dsn != "", "database.dsn must be set")
+	check(c.Database.MaxConns >= 1, "database.max_conns must be at least 1")
+	check(c.Database.MinConns >= 0 && c.Database.MinConns <= c.Database.MaxConns, "database.min_conns must be between 0 and database.max_conns")
+	check(c.Database.MaxConnLifetime > 0, "database.max_conn_lifetime must be positive")
+	check(c.Database.MaxConnIdleTime > 0, "database.max_conn_idle_time must be positive")
+	check(c.Database.HealthCheckPeriod > 0, "database.health_check_period must be positive")
+	_, ok := queryExecModes[c.Database.QueryExecMode]
+	check(ok, "database.query_exec_mode must be one of cache_statement, cache_describe, describe_exec, exec or simple_protocol, got %q", c.Database.QueryExecMode)
+	check(c.Database.StatementCacheCapacity >= 0, "database.statement_cache_capacity must not be negative")
+
+	_, err := parseLevel(c.Log.Level)
+	check(err == nil, "log.level must be one of debug, info, warn or error, got %q", c.Log.Level)
+	check(c.Log.Format == "text" || c.Log.Format == "json", "log.format must be either text or json, got %q", c.Log.Format)
+
+	_, _, err = net.SplitHostPort(c.Web.ListenAddress)
+	check(err == nil, "web.listen_address must be a host:port pair, got %q", c.Web.ListenAddress)
+	check(c.Web.ShutdownTimeout > 0, "web.shutdown_timeout must be positive")
+
+	_, _, err = net.SplitHostPort(c.Ingest.ListenAddress)
+	check(err == nil, "ingest.listen_address must be a host:port pair, got %q", c.Ingest.ListenAddress)
+	check(c.Ingest.PollInterval > 0, "ingest.poll_interval must be positive")
+	check(c.Ingest.StallAfter > c.Ingest.PollInterval, "ingest.stall_after must be longer than ingest.poll_interval")
+	check(c.Ingest.Concurrency >= 1, "ingest.concurrency must be at least 1")
+	check(c.Ingest.SourceTimeout > 0, "ingest.source_timeout must be positive")
+	check(c.Ingest.LeaseDuration > 0, "ingest.lease_duration must be positive")
+	check(c.Ingest.ShutdownTimeout > 0, "ingest.shutdown_timeout must be positive")
+	check(c.Ingest.Retry.MaxAttempts >= 1, "ingest.retry.max_attempts must be at least 1")
+	check(c.Ingest.Retry.InitialBackoff > 0, "ingest.retry.initial_backoff must be positive")
+	check(c.Ingest.Retry.MaxBackoff >= c.Ingest.Retry.InitialBackoff, "ingest.retry.max_backoff must not be shorter than ingest.retry.initial_backoff")
+	check(c.Ingest.CircuitBreaker.FailureThreshold >= 1, "ingest.circuit_breaker.failure_threshold must be at least 1")
+	check(c.Ingest.CircuitBreaker.Cooldown > 0, "ingest.circuit_breaker.cooldown must be positive")
+	check(c.Ingest.Gc.Interval > 0, "ingest.gc.interval must be positive")
+	check(c.Ingest.Gc.GracePeriod >= 0, "ingest.gc.grace_period must not be negative")
+	check(c.Ingest.Gc.BatchSize >= 1, "ingest.gc.batch_size must be at least 1")
+	check(c.Ingest.Gc.RunRetention > 0, "ingest.gc.run_retention must be positive")
+
+	_, _, err = net.SplitHostPort(c.Mock.ListenAddress)
+	check(err == nil, "mock.listen_address must be a host:port pair, got %q", c.Mock.ListenAddress)
+	check(c.Mock.StaticDir != "", "mock.static_dir must be set")
+	check(c.Mock.ShutdownTimeout > 0, "mock.shutdown_timeout must be positive")
+
+	_, err = c.Secrets.DecodeKey()
+	check(err == nil, "secrets.key must be 32 bytes encoded as base64")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w:\n%w", ErrInvalidConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func parseLevel(level string) (slog.Level, error) {
+	var result slog.Level
+	err := result.UnmarshalText([]byte(level))
+	return result, err
+}
+
+// SlogLevel returns the configured level, Validate has already made sure it parses.
+func (c LogConfig) SlogLevel() slog.Level {
+	level, _ := parseLevel(c.Level)
+	return level
+}
+
+func (c LogConfig) JSON() bool {
+	return c.Format == "json"
+}
+
+// Logger builds a logger writing to stderr in the configured format and level.
+func (c LogConfig) Logger() *slog.Logger {
+	opts := &slog.HandlerOptions{Level: c.SlogLevel()}
+
+	if c.JSON() {
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts))
+	}
+
+	return slog.New(slog.NewTextHandler(os.Stderr, opts))
+}
+
+// DecodeKey returns the raw key, or nil when no key is configured.
+func (c SecretsConfig) DecodeKey() ([]byte, error) {
+	if c.Key == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(c.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) != 32 {
+		return nil, fmt.Errorf("expected 32 bytes, got %d", len(key))
+	}
+
+	return key, nil
+}
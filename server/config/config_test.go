@@ -0,0 +1,293 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Errorf("Default().Validate() = %v", err)
+	}
+}
+
+func TestLoadWithoutFile(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Load() = %+v, want the defaults", cfg)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, `
+log:
+  level: debug
+ingest:
+  concurrency: 8
+  retry:
+    max_backoff: 1m
+  gc:
+    batch_size: 10
+`)
+
+	t.Setenv("PROPHET_INGEST_GC_BATCH_SIZE", "20")
+	t.Setenv("PROPHET_INGEST_RETRY_MAX_BACKOFF", "2m")
+	t.Setenv("PROPHET_WEB_LISTEN_ADDRESS", "0.0.0.0:8080")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := Default()
+	want.Log.Level = "debug"
+	want.Ingest.Concurrency = 8
+	want.Ingest.Retry.MaxBackoff = 2 * time.Minute
+	want.Ingest.Gc.BatchSize = 20
+	want.Web.ListenAddress = "0.0.0.0:8080"
+
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		env      map[string]string
+		messages []string
+	}{
+		{
+			name:     "unknown key",
+			content:  "ingest:\n  concurency: 4\n",
+			messages: []string{"field concurency not found"},
+		},
+		{
+			name:     "invalid yaml value",
+			content:  "ingest:\n  concurrency: many\n",
+			messages: []string{"cannot unmarshal"},
+		},
+		{
+			name:     "invalid env integer",
+			env:      map[string]string{"PROPHET_INGEST_CONCURRENCY": "many"},
+			messages: []string{"PROPHET_INGEST_CONCURRENCY: invalid integer"},
+		},
+		{
+			name:     "invalid env duration",
+			env:      map[string]string{"PROPHET_WEB_SHUTDOWN_TIMEOUT": "soon"},
+			messages: []string{"PROPHET_WEB_SHUTDOWN_TIMEOUT: invalid duration"},
+		},
+		{
+			name: "every invalid env value is reported",
+			env: map[string]string{
+				"PROPHET_INGEST_GC_BATCH_SIZE":     "x",
+				"PROPHET_DATABASE_MAX_CONNS":       "y",
+				"PROPHET_INGEST_RETRY_MAX_BACKOFF": "z",
+			},
+			messages: []string{
+				"PROPHET_INGEST_GC_BATCH_SIZE: invalid integer",
+				"PROPHET_DATABASE_MAX_CONNS: invalid integer",
+				"PROPHET_INGEST_RETRY_MAX_BACKOFF: invalid duration",
+			},
+		},
+		{
+			name:     "valid env value that fails validation",
+			env:      map[string]string{"PROPHET_INGEST_CONCURRENCY": "0"},
+			messages: []string{"ingest.concurrency must be at least 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			path := ""
+			if tt.content != "" {
+				path = writeConfig(t, tt.content)
+			}
+
+			_, err := Load(path)
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("Load() error = %v, want %v", err, ErrInvalidConfig)
+			}
+
+			for _, message := range tt.messages {
+				if !strings.Contains(err.Error(), message) {
+					t.Errorf("Load() error = %v, want it to mention %q", err, message)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load() error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	type inner struct {
+		Enabled bool          `yaml:"enabled"`
+		Timeout time.Duration `yaml:"timeout"`
+	}
+
+	type settings struct {
+		Name  string `yaml:"name"`
+		Count int    `yaml:"count,omitempty"`
+		Inner inner  `yaml:"inner"`
+		Other inner  `yaml:"other"`
+	}
+
+	t.Setenv("TEST_NAME", "prophet")
+	t.Setenv("TEST_COUNT", "3")
+	t.Setenv("TEST_INNER_ENABLED", "true")
+	t.Setenv("TEST_INNER_TIMEOUT", "90s")
+
+	got := settings{Other: inner{Enabled: true, Timeout: time.Second}}
+	err := applyEnv(reflect.ValueOf(&got).Elem(), "TEST")
+	if err != nil {
+		t.Fatalf("applyEnv() error = %v", err)
+	}
+
+	want := settings{
+		Name:  "prophet",
+		Count: 3,
+		Inner: inner{Enabled: true, Timeout: 90 * time.Second},
+		Other: inner{Enabled: true, Timeout: time.Second},
+	}
+
+	if got != want {
+		t.Errorf("applyEnv() = %+v, want %+v", got, want)
+	}
+
+	t.Setenv("TEST_INNER_ENABLED", "maybe")
+	err = applyEnv(reflect.ValueOf(&got).Elem(), "TEST")
+	if err == nil || !strings.Contains(err.Error(), "TEST_INNER_ENABLED: invalid boolean") {
+		t.Errorf("applyEnv() error = %v, want an invalid boolean error", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		message string
+	}{
+		{"database dsn", func(c *Config) { c.Database.Dsn = "" }, "database.dsn must be set"},
+		{"database max conns", func(c *Config) { c.Database.MaxConns = 0 }, "database.max_conns must be at least 1"},
+		{"database min conns negative", func(c *Config) { c.Database.MinConns = -1 }, "database.min_conns must be between"},
+		{"database min conns above max", func(c *Config) { c.Database.MinConns = 11 }, "database.min_conns must be between"},
+		{"database max conn lifetime", func(c *Config) { c.Database.MaxConnLifetime = 0 }, "database.max_conn_lifetime must be positive"},
+		{"database max conn idle time", func(c *Config) { c.Database.MaxConnIdleTime = 0 }, "database.max_conn_idle_time must be positive"},
+		{"database health check period", func(c *Config) { c.Database.HealthCheckPeriod = 0 }, "database.health_check_period must be positive"},
+		{"database query exec mode", func(c *Config) { c.Database.QueryExecMode = "fast" }, "database.query_exec_mode must be one of"},
+		{"database statement cache", func(c *Config) { c.Database.StatementCacheCapacity = -1 }, "database.statement_cache_capacity must not be negative"},
+		{"log level", func(c *Config) { c.Log.Level = "verbose" }, "log.level must be one of"},
+		{"log format", func(c *Config) { c.Log.Format = "xml" }, "log.format must be either text or json"},
+		{"web listen address", func(c *Config) { c.Web.ListenAddress = "3333" }, "web.listen_address must be a host:port pair"},
+		{"web shutdown timeout", func(c *Config) { c.Web.ShutdownTimeout = 0 }, "web.shutdown_timeout must be positive"},
+		{"ingest listen address", func(c *Config) { c.Ingest.ListenAddress = "" }, "ingest.listen_address must be a host:port pair"},
+		{"ingest poll interval", func(c *Config) { c.Ingest.PollInterval = 0 }, "ingest.poll_interval must be positive"},
+		{"ingest stall after", func(c *Config) { c.Ingest.StallAfter = c.Ingest.PollInterval }, "ingest.stall_after must be longer than ingest.poll_interval"},
+		{"ingest concurrency", func(c *Config) { c.Ingest.Concurrency = 0 }, "ingest.concurrency must be at least 1"},
+		{"ingest source timeout", func(c *Config) { c.Ingest.SourceTimeout = 0 }, "ingest.source_timeout must be positive"},
+		{"ingest lease duration", func(c *Config) { c.Ingest.LeaseDuration = 0 }, "ingest.lease_duration must be positive"},
+		{"ingest shutdown timeout", func(c *Config) { c.Ingest.ShutdownTimeout = 0 }, "ingest.shutdown_timeout must be positive"},
+		{"ingest retry max attempts", func(c *Config) { c.Ingest.Retry.MaxAttempts = 0 }, "ingest.retry.max_attempts must be at least 1"},
+		{"ingest retry initial backoff", func(c *Config) { c.Ingest.Retry.InitialBackoff = 0 }, "ingest.retry.initial_backoff must be positive"},
+		{"ingest retry max backoff", func(c *Config) { c.Ingest.Retry.MaxBackoff = time.Millisecond }, "ingest.retry.max_backoff must not be shorter"},
+		{"ingest failure threshold", func(c *Config) { c.Ingest.CircuitBreaker.FailureThreshold = 0 }, "ingest.circuit_breaker.failure_threshold must be at least 1"},
+		{"ingest cooldown", func(c *Config) { c.Ingest.CircuitBreaker.Cooldown = 0 }, "ingest.circuit_breaker.cooldown must be positive"},
+		{"ingest gc interval", func(c *Config) { c.Ingest.Gc.Interval = 0 }, "ingest.gc.interval must be positive"},
+		{"ingest gc grace period", func(c *Config) { c.Ingest.Gc.GracePeriod = -time.Second }, "ingest.gc.grace_period must not be negative"},
+		{"ingest gc batch size", func(c *Config) { c.Ingest.Gc.BatchSize = 0 }, "ingest.gc.batch_size must be at least 1"},
+		{"ingest gc run retention", func(c *Config) { c.Ingest.Gc.RunRetention = 0 }, "ingest.gc.run_retention must be positive"},
+		{"mock listen address", func(c *Config) { c.Mock.ListenAddress = "localhost" }, "mock.listen_address must be a host:port pair"},
+		{"mock static dir", func(c *Config) { c.Mock.StaticDir = "" }, "mock.static_dir must be set"},
+		{"mock shutdown timeout", func(c *Config) { c.Mock.ShutdownTimeout = 0 }, "mock.shutdown_timeout must be positive"},
+		{"secrets key encoding", func(c *Config) { c.Secrets.Key = "not base64!" }, "secrets.key must be 32 bytes encoded as base64"},
+		{"secrets key length", func(c *Config) { c.Secrets.Key = "c2hvcnQ=" }, "secrets.key must be 32 bytes encoded as base64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Fatalf("Validate() error = %v, want %v", err, ErrInvalidConfig)
+			}
+
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Validate() error = %v, want it to mention %q", err, tt.message)
+			}
+
+			// Only the broken setting is reported.
+			if lines := strings.Count(err.Error(), "\n"); lines != 1 {
+				t.Errorf("Validate() reported %d errors, want 1: %v", lines, err)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryError(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Dsn = ""
+	cfg.Log.Format = "xml"
+	cfg.Mock.StaticDir = ""
+
+	err := cfg.Validate()
+	for _, message := range []string{"database.dsn", "log.format", "mock.static_dir"} {
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("Validate() error = %v, want it to mention %q", err, message)
+		}
+	}
+}
+
+func TestDecodeKey(t *testing.T) {
+	key, err := SecretsConfig{}.DecodeKey()
+	if key != nil || err != nil {
+		t.Errorf("DecodeKey() = %v, %v, want no key", key, err)
+	}
+
+	key, err = SecretsConfig{Key: "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="}.DecodeKey()
+	if err != nil {
+		t.Fatalf("DecodeKey() error = %v", err)
+	}
+
+	for i, b := range key {
+		if int(b) != i {
+			t.Fatalf("DecodeKey() = %v, want bytes 0 to 31", key)
+		}
+	}
+
+	if len(key) != 32 {
+		t.Errorf("DecodeKey() returned %d bytes, want 32", len(key))
+	}
+}
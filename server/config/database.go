@@ -0,0 +1,34 @@
+package config
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var queryExecModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// PoolConfig parses the DSN and applies the pool and statement cache settings.
+func (c DatabaseConfig) PoolConfig() (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(c.Dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	poolConfig.MaxConns = int32(c.MaxConns)
+	poolConfig.MinConns = int32(c.MinConns)
+	poolConfig.MaxConnLifetime = c.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = c.MaxConnIdleTime
+	poolConfig.HealthCheckPeriod = c.HealthCheckPeriod
+
+	poolConfig.ConnConfig.DefaultQueryExecMode = queryExecModes[c.QueryExecMode]
+	poolConfig.ConnConfig.StatementCacheCapacity = c.StatementCacheCapacity
+	poolConfig.ConnConfig.DescriptionCacheCapacity = c.StatementCacheCapacity
+
+	return poolConfig, nil
+}
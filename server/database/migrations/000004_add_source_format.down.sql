@@ -0,0 +1,3 @@
+ALTER TABLE sources 
+DROP COLUMN IF EXISTS format,
+DROP COLUMN IF EXISTS format_options;
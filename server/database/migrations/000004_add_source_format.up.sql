@@ -0,0 +1,3 @@
+ALTER TABLE sources 
+ADD COLUMN IF NOT EXISTS format VARCHAR(32) NOT NULL DEFAULT 'csv',
+ADD COLUMN IF NOT EXISTS format_options JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,4 @@
+ALTER TABLE sources 
+DROP COLUMN IF EXISTS last_success,
+DROP COLUMN IF EXISTS last_error,
+DROP COLUMN IF EXISTS consecutive_failures;
@@ -0,0 +1,4 @@
+ALTER TABLE sources 
+ADD COLUMN IF NOT EXISTS last_success TIMESTAMP,
+ADD COLUMN IF NOT EXISTS last_error TEXT,
+ADD COLUMN IF NOT EXISTS consecutive_failures INT NOT NULL DEFAULT 0;
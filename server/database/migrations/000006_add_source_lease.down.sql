@@ -0,0 +1,3 @@
+ALTER TABLE sources 
+DROP COLUMN IF EXISTS lease_owner,
+DROP COLUMN IF EXISTS lease_expires_at;
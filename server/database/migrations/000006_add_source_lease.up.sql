@@ -0,0 +1,3 @@
+ALTER TABLE sources 
+ADD COLUMN IF NOT EXISTS lease_owner VARCHAR(255),
+ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP;
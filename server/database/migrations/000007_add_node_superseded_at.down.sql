@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_nodes_superseded_at;
+
+ALTER TABLE nodes 
+DROP COLUMN IF EXISTS superseded_at;
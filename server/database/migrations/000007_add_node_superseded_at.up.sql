@@ -0,0 +1,4 @@
+ALTER TABLE nodes 
+ADD COLUMN IF NOT EXISTS superseded_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_nodes_superseded_at ON nodes (superseded_at) WHERE superseded_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE node_events;
+
+ALTER TABLE nodes 
+DROP COLUMN IF EXISTS last_seen;
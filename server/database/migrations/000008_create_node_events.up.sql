@@ -0,0 +1,19 @@
+ALTER TABLE nodes 
+ADD COLUMN IF NOT EXISTS last_seen TIMESTAMP NOT NULL DEFAULT now();
+
+CREATE TABLE IF NOT EXISTS node_events (
+    id BIGSERIAL PRIMARY KEY,
+    ip_addr INET NOT NULL,
+    source_id INT NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    event VARCHAR(16) NOT NULL CHECK (event IN ('appeared', 'disappeared')),
+    occurred_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_events_ip_addr ON node_events (ip_addr, id);
+
+-- Nodes that are visible today have been around since at least their source's last execution
+INSERT INTO node_events (ip_addr, source_id, event, occurred_at)
+SELECT n.ip_addr, n.source_id, 'appeared', COALESCE(s.last_execution, now())
+FROM nodes n
+INNER JOIN sources s ON s.id = n.source_id
+WHERE s.version < n.version;
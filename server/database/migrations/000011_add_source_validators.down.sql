@@ -0,0 +1,4 @@
+ALTER TABLE sources 
+DROP COLUMN IF EXISTS etag,
+DROP COLUMN IF EXISTS last_modified,
+DROP COLUMN IF EXISTS content_hash;
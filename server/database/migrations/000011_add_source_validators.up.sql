@@ -0,0 +1,4 @@
+ALTER TABLE sources 
+ADD COLUMN IF NOT EXISTS etag TEXT,
+ADD COLUMN IF NOT EXISTS last_modified TEXT,
+ADD COLUMN IF NOT EXISTS content_hash BYTEA;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS ingestion_runs (
+    id BIGSERIAL PRIMARY KEY,
+    source_id INT NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'success', 'unchanged', 'failure')),
+    error TEXT,
+    requested_at TIMESTAMP NOT NULL DEFAULT now(),
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ingestion_runs_open ON ingestion_runs (source_id) WHERE status IN ('pending', 'running');
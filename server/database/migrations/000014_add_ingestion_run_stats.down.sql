@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_ingestion_runs_source_id;
+DROP INDEX IF EXISTS idx_ingestion_runs_finished_at;
+
+DELETE FROM ingestion_runs WHERE trigger = 'scheduled';
+
+ALTER TABLE ingestion_runs 
+DROP COLUMN IF EXISTS trigger,
+DROP COLUMN IF EXISTS version,
+DROP COLUMN IF EXISTS http_status,
+DROP COLUMN IF EXISTS bytes_fetched,
+DROP COLUMN IF EXISTS rows_parsed,
+DROP COLUMN IF EXISTS rows_rejected,
+DROP COLUMN IF EXISTS nodes_added,
+DROP COLUMN IF EXISTS nodes_removed;
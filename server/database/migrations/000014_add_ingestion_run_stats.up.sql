@@ -0,0 +1,13 @@
+-- Runs recorded before this migration were all requested through the api
+ALTER TABLE ingestion_runs 
+ADD COLUMN IF NOT EXISTS trigger VARCHAR(16) NOT NULL DEFAULT 'manual' CHECK (trigger IN ('manual', 'scheduled')),
+ADD COLUMN IF NOT EXISTS version BIGINT,
+ADD COLUMN IF NOT EXISTS http_status INT,
+ADD COLUMN IF NOT EXISTS bytes_fetched BIGINT,
+ADD COLUMN IF NOT EXISTS rows_parsed INT,
+ADD COLUMN IF NOT EXISTS rows_rejected INT,
+ADD COLUMN IF NOT EXISTS nodes_added INT,
+ADD COLUMN IF NOT EXISTS nodes_removed INT;
+
+CREATE INDEX IF NOT EXISTS idx_ingestion_runs_source_id ON ingestion_runs (source_id, id);
+CREATE INDEX IF NOT EXISTS idx_ingestion_runs_finished_at ON ingestion_runs (finished_at);
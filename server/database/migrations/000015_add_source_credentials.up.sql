@@ -0,0 +1,2 @@
+ALTER TABLE sources 
+ADD COLUMN IF NOT EXISTS credentials BYTEA;
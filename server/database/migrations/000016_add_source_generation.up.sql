@@ -0,0 +1,2 @@
+ALTER TABLE sources 
+ADD COLUMN IF NOT EXISTS generation BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE sources 
+ADD COLUMN IF NOT EXISTS last_confirmed_at TIMESTAMP;
@@ -129,11 +129,12 @@ func (q *Queries) ListEntriesForAllowList(ctx context.Context, listID int32) ([]
 	return items, nil
 }
 
-const removeFromAllowlist = `-- name: RemoveFromAllowlist :exec
+const removeFromAllowlist = `-- name: RemoveFromAllowlist :one
 DELETE FROM allowlist_entry 
 WHERE 1=1
 AND id = $1 
 AND list_id = $2
+RETURNING id, cidr, list_id
 `
 
 type RemoveFromAllowlistParams struct {
@@ -141,7 +142,9 @@ type RemoveFromAllowlistParams struct {
 	ListID int32
 }
 
-func (q *Queries) RemoveFromAllowlist(ctx context.Context, arg RemoveFromAllowlistParams) error {
-	_, err := q.db.Exec(ctx, removeFromAllowlist, arg.ID, arg.ListID)
-	return err
+func (q *Queries) RemoveFromAllowlist(ctx context.Context, arg RemoveFromAllowlistParams) (AllowlistEntry, error) {
+	row := q.db.QueryRow(ctx, removeFromAllowlist, arg.ID, arg.ListID)
+	var i AllowlistEntry
+	err := row.Scan(&i.ID, &i.Cidr, &i.ListID)
+	return i, err
 }
@@ -0,0 +1,261 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: api_keys.sql
+
+package database
+
+import (
+	"context"
+)
+
+const createApiKey = `-- name: CreateApiKey :one
+INSERT INTO api_keys (name, prefix, key_hash, scopes)
+VALUES ($1, $2, $3, $4)
+RETURNING id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at
+`
+
+type CreateApiKeyParams struct {
+	Name    string
+	Prefix  string
+	KeyHash []byte
+	Scopes  []string
+}
+
+func (q *Queries) CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, createApiKey,
+		arg.Name,
+		arg.Prefix,
+		arg.KeyHash,
+		arg.Scopes,
+	)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const createBootstrapApiKey = `-- name: CreateBootstrapApiKey :one
+INSERT INTO api_keys (name, prefix, key_hash, scopes)
+SELECT $1, $2, $3, $4::text[]
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM api_keys
+    WHERE 1=1
+    AND revoked_at IS NULL
+)
+RETURNING id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at
+`
+
+type CreateBootstrapApiKeyParams struct {
+	Name    string
+	Prefix  string
+	KeyHash []byte
+	Scopes  []string
+}
+
+// Fails with no rows if an active key already exists.
+func (q *Queries) CreateBootstrapApiKey(ctx context.Context, arg CreateBootstrapApiKeyParams) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, createBootstrapApiKey,
+		arg.Name,
+		arg.Prefix,
+		arg.KeyHash,
+		arg.Scopes,
+	)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const getActiveApiKeyByHash = `-- name: GetActiveApiKeyByHash :one
+SELECT id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at
+FROM api_keys
+WHERE 1=1
+AND key_hash = $1
+AND revoked_at IS NULL
+LIMIT 1
+`
+
+func (q *Queries) GetActiveApiKeyByHash(ctx context.Context, keyHash []byte) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, getActiveApiKeyByHash, keyHash)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const getApiKey = `-- name: GetApiKey :one
+SELECT id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at
+FROM api_keys
+WHERE 1=1
+AND id = $1
+`
+
+func (q *Queries) GetApiKey(ctx context.Context, id int32) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, getApiKey, id)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const listApiKeys = `-- name: ListApiKeys :many
+SELECT id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at
+FROM api_keys
+WHERE 1=1
+AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListApiKeysParams struct {
+	ID    int32
+	Limit int32
+}
+
+func (q *Queries) ListApiKeys(ctx context.Context, arg ListApiKeysParams) ([]ApiKey, error) {
+	rows, err := q.db.Query(ctx, listApiKeys, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApiKey
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Prefix,
+			&i.KeyHash,
+			&i.Scopes,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockApiKeyBootstrap = `-- name: LockApiKeyBootstrap :exec
+SELECT pg_advisory_xact_lock(hashtext('prophet_api_key_bootstrap'))
+`
+
+// Held until the end of the transaction so that concurrent bootstraps check for
+// an active key one after the other.
+func (q *Queries) LockApiKeyBootstrap(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockApiKeyBootstrap)
+	return err
+}
+
+const revokeApiKey = `-- name: RevokeApiKey :one
+UPDATE api_keys
+SET revoked_at = COALESCE(revoked_at, now())
+WHERE 1=1
+AND id = $1
+RETURNING id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at
+`
+
+func (q *Queries) RevokeApiKey(ctx context.Context, id int32) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, revokeApiKey, id)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const rotateApiKey = `-- name: RotateApiKey :one
+WITH revoked AS (
+    UPDATE api_keys
+    SET revoked_at = now()
+    WHERE 1=1
+    AND api_keys.id = $3
+    AND api_keys.revoked_at IS NULL
+    RETURNING api_keys.name, api_keys.scopes
+)
+INSERT INTO api_keys (name, prefix, key_hash, scopes)
+SELECT revoked.name, $1, $2, revoked.scopes
+FROM revoked
+RETURNING id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at
+`
+
+type RotateApiKeyParams struct {
+	Prefix  string
+	KeyHash []byte
+	ID      int32
+}
+
+func (q *Queries) RotateApiKey(ctx context.Context, arg RotateApiKeyParams) (ApiKey, error) {
+	row := q.db.QueryRow(ctx, rotateApiKey, arg.Prefix, arg.KeyHash, arg.ID)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const touchApiKey = `-- name: TouchApiKey :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE 1=1
+AND id = $1
+AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')
+`
+
+func (q *Queries) TouchApiKey(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, touchApiKey, id)
+	return err
+}
@@ -0,0 +1,105 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: audit_log.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const insertAuditLog = `-- name: InsertAuditLog :exec
+INSERT INTO audit_log (actor, actor_key_id, request_id, operation, resource_type, resource_id, before, after)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type InsertAuditLogParams struct {
+	Actor        string
+	ActorKeyID   pgtype.Int4
+	RequestID    string
+	Operation    string
+	ResourceType string
+	ResourceID   string
+	Before       []byte
+	After        []byte
+}
+
+func (q *Queries) InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) error {
+	_, err := q.db.Exec(ctx, insertAuditLog,
+		arg.Actor,
+		arg.ActorKeyID,
+		arg.RequestID,
+		arg.Operation,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.Before,
+		arg.After,
+	)
+	return err
+}
+
+const listAuditLog = `-- name: ListAuditLog :many
+SELECT id, actor, actor_key_id, request_id, operation, resource_type, resource_id, before, after, occurred_at
+FROM audit_log
+WHERE 1=1
+AND id < $1
+AND ($2::text IS NULL OR actor = $2)
+AND ($3::text IS NULL OR resource_type = $3)
+AND ($4::text IS NULL OR resource_id = $4)
+AND ($5::timestamp IS NULL OR occurred_at >= $5)
+AND ($6::timestamp IS NULL OR occurred_at < $6)
+ORDER BY id DESC
+LIMIT $7
+`
+
+type ListAuditLogParams struct {
+	BeforeID     int64
+	Actor        pgtype.Text
+	ResourceType pgtype.Text
+	ResourceID   pgtype.Text
+	Since        pgtype.Timestamp
+	Until        pgtype.Timestamp
+	MaxResults   int32
+}
+
+func (q *Queries) ListAuditLog(ctx context.Context, arg ListAuditLogParams) ([]AuditLog, error) {
+	rows, err := q.db.Query(ctx, listAuditLog,
+		arg.BeforeID,
+		arg.Actor,
+		arg.ResourceType,
+		arg.ResourceID,
+		arg.Since,
+		arg.Until,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Actor,
+			&i.ActorKeyID,
+			&i.RequestID,
+			&i.Operation,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.Before,
+			&i.After,
+			&i.OccurredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
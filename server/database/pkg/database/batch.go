@@ -23,7 +23,7 @@ INSERT INTO nodes (ip_addr, source_id, version)
 VALUES ($1, $2, $3) 
 ON CONFLICT(ip_addr, source_id) 
 DO UPDATE 
-SET version = EXCLUDED.version
+SET version = EXCLUDED.version, superseded_at = NULL, last_seen = now()
 `
 
 type BatchInsertNodesBatchResults struct {
@@ -0,0 +1,44 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: copyfrom.go
+
+package database
+
+import (
+	"context"
+)
+
+// iteratorForInsertNodeEvents implements pgx.CopyFromSource.
+type iteratorForInsertNodeEvents struct {
+	rows                 []InsertNodeEventsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForInsertNodeEvents) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForInsertNodeEvents) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].IpAddr,
+		r.rows[0].SourceID,
+		r.rows[0].Event,
+	}, nil
+}
+
+func (r iteratorForInsertNodeEvents) Err() error {
+	return nil
+}
+
+func (q *Queries) InsertNodeEvents(ctx context.Context, arg []InsertNodeEventsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"node_events"}, []string{"ip_addr", "source_id", "event"}, &iteratorForInsertNodeEvents{rows: arg})
+}
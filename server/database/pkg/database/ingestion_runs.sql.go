@@ -0,0 +1,271 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: ingestion_runs.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createIngestionRun = `-- name: CreateIngestionRun :one
+INSERT INTO ingestion_runs (source_id)
+VALUES ($1)
+RETURNING id, source_id, status, error, requested_at, started_at, finished_at, trigger, version, http_status, bytes_fetched, rows_parsed, rows_rejected, nodes_added, nodes_removed
+`
+
+func (q *Queries) CreateIngestionRun(ctx context.Context, sourceID int32) (IngestionRun, error) {
+	row := q.db.QueryRow(ctx, createIngestionRun, sourceID)
+	var i IngestionRun
+	err := row.Scan(
+		&i.ID,
+		&i.SourceID,
+		&i.Status,
+		&i.Error,
+		&i.RequestedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Trigger,
+		&i.Version,
+		&i.HttpStatus,
+		&i.BytesFetched,
+		&i.RowsParsed,
+		&i.RowsRejected,
+		&i.NodesAdded,
+		&i.NodesRemoved,
+	)
+	return i, err
+}
+
+const deleteExpiredIngestionRuns = `-- name: DeleteExpiredIngestionRuns :execrows
+DELETE FROM ingestion_runs
+WHERE id IN (
+    SELECT r.id
+    FROM ingestion_runs r
+    WHERE 1=1
+    AND r.finished_at < now() - $1::interval
+    LIMIT $2
+)
+`
+
+type DeleteExpiredIngestionRunsParams struct {
+	Retention pgtype.Interval
+	BatchSize int32
+}
+
+func (q *Queries) DeleteExpiredIngestionRuns(ctx context.Context, arg DeleteExpiredIngestionRunsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredIngestionRuns, arg.Retention, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const finishIngestionRuns = `-- name: FinishIngestionRuns :exec
+UPDATE ingestion_runs
+SET 
+    status = $1, 
+    error = $2, 
+    finished_at = now(),
+    version = $3::bigint,
+    http_status = $4,
+    bytes_fetched = $5::bigint,
+    rows_parsed = $6::int,
+    rows_rejected = $7::int,
+    nodes_added = $8::int,
+    nodes_removed = $9::int
+WHERE id = ANY($10::bigint[])
+`
+
+type FinishIngestionRunsParams struct {
+	Status       string
+	Error        pgtype.Text
+	Version      int64
+	HttpStatus   pgtype.Int4
+	BytesFetched int64
+	RowsParsed   int32
+	RowsRejected int32
+	NodesAdded   int32
+	NodesRemoved int32
+	Ids          []int64
+}
+
+// `version` is the source version visible once the ingestion finished.
+func (q *Queries) FinishIngestionRuns(ctx context.Context, arg FinishIngestionRunsParams) error {
+	_, err := q.db.Exec(ctx, finishIngestionRuns,
+		arg.Status,
+		arg.Error,
+		arg.Version,
+		arg.HttpStatus,
+		arg.BytesFetched,
+		arg.RowsParsed,
+		arg.RowsRejected,
+		arg.NodesAdded,
+		arg.NodesRemoved,
+		arg.Ids,
+	)
+	return err
+}
+
+const getIngestionRun = `-- name: GetIngestionRun :one
+SELECT id, source_id, status, error, requested_at, started_at, finished_at, trigger, version, http_status, bytes_fetched, rows_parsed, rows_rejected, nodes_added, nodes_removed
+FROM ingestion_runs
+WHERE 1=1
+AND id = $1
+`
+
+func (q *Queries) GetIngestionRun(ctx context.Context, id int64) (IngestionRun, error) {
+	row := q.db.QueryRow(ctx, getIngestionRun, id)
+	var i IngestionRun
+	err := row.Scan(
+		&i.ID,
+		&i.SourceID,
+		&i.Status,
+		&i.Error,
+		&i.RequestedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Trigger,
+		&i.Version,
+		&i.HttpStatus,
+		&i.BytesFetched,
+		&i.RowsParsed,
+		&i.RowsRejected,
+		&i.NodesAdded,
+		&i.NodesRemoved,
+	)
+	return i, err
+}
+
+const getPendingIngestionRun = `-- name: GetPendingIngestionRun :one
+SELECT id, source_id, status, error, requested_at, started_at, finished_at, trigger, version, http_status, bytes_fetched, rows_parsed, rows_rejected, nodes_added, nodes_removed
+FROM ingestion_runs
+WHERE 1=1
+AND source_id = $1
+AND status = 'pending'
+ORDER BY id
+LIMIT 1
+`
+
+func (q *Queries) GetPendingIngestionRun(ctx context.Context, sourceID int32) (IngestionRun, error) {
+	row := q.db.QueryRow(ctx, getPendingIngestionRun, sourceID)
+	var i IngestionRun
+	err := row.Scan(
+		&i.ID,
+		&i.SourceID,
+		&i.Status,
+		&i.Error,
+		&i.RequestedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.Trigger,
+		&i.Version,
+		&i.HttpStatus,
+		&i.BytesFetched,
+		&i.RowsParsed,
+		&i.RowsRejected,
+		&i.NodesAdded,
+		&i.NodesRemoved,
+	)
+	return i, err
+}
+
+const listSourceIngestionRuns = `-- name: ListSourceIngestionRuns :many
+SELECT id, source_id, status, error, requested_at, started_at, finished_at, trigger, version, http_status, bytes_fetched, rows_parsed, rows_rejected, nodes_added, nodes_removed
+FROM ingestion_runs
+WHERE 1=1
+AND source_id = $1
+AND id < $2
+ORDER BY id DESC
+LIMIT $3
+`
+
+type ListSourceIngestionRunsParams struct {
+	SourceID   int32
+	BeforeID   int64
+	MaxResults int32
+}
+
+func (q *Queries) ListSourceIngestionRuns(ctx context.Context, arg ListSourceIngestionRunsParams) ([]IngestionRun, error) {
+	rows, err := q.db.Query(ctx, listSourceIngestionRuns, arg.SourceID, arg.BeforeID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []IngestionRun
+	for rows.Next() {
+		var i IngestionRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.SourceID,
+			&i.Status,
+			&i.Error,
+			&i.RequestedAt,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Trigger,
+			&i.Version,
+			&i.HttpStatus,
+			&i.BytesFetched,
+			&i.RowsParsed,
+			&i.RowsRejected,
+			&i.NodesAdded,
+			&i.NodesRemoved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const startRequestedIngestionRuns = `-- name: StartRequestedIngestionRuns :many
+UPDATE ingestion_runs
+SET status = 'running', started_at = now()
+WHERE 1=1
+AND source_id = $1
+AND status IN ('pending', 'running')
+RETURNING id
+`
+
+// Marks every run requested for the source as started by the ingestion that
+// holds its lease. Runs still marked running belong to an ingestion that was
+// cancelled or died, they are taken over as well.
+func (q *Queries) StartRequestedIngestionRuns(ctx context.Context, sourceID int32) ([]int64, error) {
+	rows, err := q.db.Query(ctx, startRequestedIngestionRuns, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const startScheduledIngestionRun = `-- name: StartScheduledIngestionRun :one
+INSERT INTO ingestion_runs (source_id, trigger, status, started_at)
+VALUES ($1, 'scheduled', 'running', now())
+RETURNING id
+`
+
+func (q *Queries) StartScheduledIngestionRun(ctx context.Context, sourceID int32) (int64, error) {
+	row := q.db.QueryRow(ctx, startScheduledIngestionRun, sourceID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
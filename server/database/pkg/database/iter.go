@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+)
+
+// ExportNodesEach runs the ExportNodes query but hands each row to fn as it is
+// read off the connection instead of collecting the whole result set, so the
+// full node list can be streamed to a client in constant memory. Iteration
+// stops at the first error returned by fn.
+func (q *Queries) ExportNodesEach(ctx context.Context, arg ExportNodesParams, fn func(ExportNodesRow) error) error {
+	rows, err := q.db.Query(ctx, exportNodes, arg.ListID, arg.Invert)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i ExportNodesRow
+		if err := rows.Scan(&i.IpAddr, &i.SourceIds, &i.LastSeen); err != nil {
+			return err
+		}
+
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -21,19 +21,85 @@ type AllowlistEntry struct {
 	ListID int32
 }
 
+type ApiKey struct {
+	ID         int32
+	Name       string
+	Prefix     string
+	KeyHash    []byte
+	Scopes     []string
+	CreatedAt  pgtype.Timestamp
+	LastUsedAt pgtype.Timestamp
+	RevokedAt  pgtype.Timestamp
+}
+
+type AuditLog struct {
+	ID           int64
+	Actor        string
+	ActorKeyID   pgtype.Int4
+	RequestID    string
+	Operation    string
+	ResourceType string
+	ResourceID   string
+	Before       []byte
+	After        []byte
+	OccurredAt   pgtype.Timestamp
+}
+
+type IngestionRun struct {
+	ID           int64
+	SourceID     int32
+	Status       string
+	Error        pgtype.Text
+	RequestedAt  pgtype.Timestamp
+	StartedAt    pgtype.Timestamp
+	FinishedAt   pgtype.Timestamp
+	Trigger      string
+	Version      pgtype.Int8
+	HttpStatus   pgtype.Int4
+	BytesFetched pgtype.Int8
+	RowsParsed   pgtype.Int4
+	RowsRejected pgtype.Int4
+	NodesAdded   pgtype.Int4
+	NodesRemoved pgtype.Int4
+}
+
 type Node struct {
-	ID       int32
-	IpAddr   netip.Addr
-	SourceID int32
-	Version  pgtype.Int8
+	ID           int32
+	IpAddr       netip.Addr
+	SourceID     int32
+	Version      pgtype.Int8
+	SupersededAt pgtype.Timestamp
+	LastSeen     pgtype.Timestamp
+}
+
+type NodeEvent struct {
+	ID         int64
+	IpAddr     netip.Addr
+	SourceID   int32
+	Event      string
+	OccurredAt pgtype.Timestamp
 }
 
 type Source struct {
-	ID            int32
-	Name          string
-	Url           string
-	Period        pgtype.Interval
-	LastExecution pgtype.Timestamp
-	Version       pgtype.Int8
-	Running       pgtype.Bool
+	ID                  int32
+	Name                string
+	Url                 string
+	Period              pgtype.Interval
+	LastExecution       pgtype.Timestamp
+	Version             pgtype.Int8
+	Running             pgtype.Bool
+	Format              string
+	FormatOptions       []byte
+	LastSuccess         pgtype.Timestamp
+	LastError           pgtype.Text
+	ConsecutiveFailures int32
+	LeaseOwner          pgtype.Text
+	LeaseExpiresAt      pgtype.Timestamp
+	Etag                pgtype.Text
+	LastModified        pgtype.Text
+	ContentHash         []byte
+	PausedUntil         pgtype.Timestamp
+	Credentials         []byte
+	Generation          int64
+	LastConfirmedAt     pgtype.Timestamp
 }
@@ -0,0 +1,68 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: node_events.sql
+
+package database
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type InsertNodeEventsParams struct {
+	IpAddr   netip.Addr
+	SourceID int32
+	Event    string
+}
+
+const listNodeEvents = `-- name: ListNodeEvents :many
+SELECT id, ip_addr, source_id, event, occurred_at
+FROM node_events
+WHERE 1=1
+AND ip_addr = $1
+AND id > $2
+AND ($4::int IS NULL OR source_id = $4)
+ORDER BY id
+LIMIT $3
+`
+
+type ListNodeEventsParams struct {
+	IpAddr   netip.Addr
+	ID       int64
+	Limit    int32
+	SourceID pgtype.Int4
+}
+
+func (q *Queries) ListNodeEvents(ctx context.Context, arg ListNodeEventsParams) ([]NodeEvent, error) {
+	rows, err := q.db.Query(ctx, listNodeEvents,
+		arg.IpAddr,
+		arg.ID,
+		arg.Limit,
+		arg.SourceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NodeEvent
+	for rows.Next() {
+		var i NodeEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.IpAddr,
+			&i.SourceID,
+			&i.Event,
+			&i.OccurredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
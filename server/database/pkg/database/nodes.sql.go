@@ -12,44 +12,116 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const listAllNodes = `-- name: ListAllNodes :many
-SELECT n.ip_addr, n.source_id, n.version, s.last_execution
+const countAggregatedNodes = `-- name: CountAggregatedNodes :one
+SELECT COUNT(DISTINCT n.ip_addr)
 FROM nodes n
 INNER JOIN sources s ON s.id = n.source_id
-WHERE 1=1 
+WHERE 1=1
 AND s.version < n.version 
-AND n.ip_addr > $1
+AND ($1::int IS NULL OR n.source_id = $1)
+AND (
+    $2::int IS NULL
+    OR $3::boolean <> (n.ip_addr <<= ANY (
+        SELECT a.cidr
+        FROM allowlist_entry a 
+        WHERE 1=1 
+        AND a.list_id = $2
+    ))
+)
+`
+
+type CountAggregatedNodesParams struct {
+	SourceID pgtype.Int4
+	ListID   pgtype.Int4
+	Invert   bool
+}
+
+func (q *Queries) CountAggregatedNodes(ctx context.Context, arg CountAggregatedNodesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countAggregatedNodes, arg.SourceID, arg.ListID, arg.Invert)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteSupersededNodes = `-- name: DeleteSupersededNodes :execrows
+DELETE FROM nodes n
+USING sources s
+WHERE 1=1
+AND s.id = n.source_id
+AND n.version <= s.version
+AND n.superseded_at < now() - $1::interval
+AND n.id IN (
+    SELECT sn.id
+    FROM nodes sn
+    INNER JOIN sources ss ON ss.id = sn.source_id
+    WHERE 1=1
+    AND sn.version <= ss.version
+    AND sn.superseded_at < now() - $1::interval
+    LIMIT $2
+)
+`
+
+type DeleteSupersededNodesParams struct {
+	GracePeriod pgtype.Interval
+	BatchSize   int32
+}
+
+// The conditions are repeated on the delete target so that a row republished
+// by a concurrent ingestion is checked again once its lock is released, the
+// subquery only picks the batch.
+func (q *Queries) DeleteSupersededNodes(ctx context.Context, arg DeleteSupersededNodesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSupersededNodes, arg.GracePeriod, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const exportNodes = `-- name: ExportNodes :many
+SELECT 
+    n.ip_addr,
+    array_agg(n.source_id ORDER BY n.source_id)::int[] AS source_ids,
+    MAX(GREATEST(n.last_seen, s.last_confirmed_at))::timestamp AS last_seen
+FROM nodes n
+INNER JOIN sources s ON s.id = n.source_id
+WHERE 1=1
+AND s.version < n.version 
+AND (
+    $1::int IS NULL
+    OR $2::boolean <> (n.ip_addr <<= ANY (
+        SELECT a.cidr
+        FROM allowlist_entry a 
+        WHERE 1=1 
+        AND a.list_id = $1
+    ))
+)
+GROUP BY n.ip_addr
 ORDER BY n.ip_addr
-LIMIT $2
 `
 
-type ListAllNodesParams struct {
-	IpAddr netip.Addr
-	Limit  int32
+type ExportNodesParams struct {
+	ListID pgtype.Int4
+	Invert bool
 }
 
-type ListAllNodesRow struct {
-	IpAddr        netip.Addr
-	SourceID      int32
-	Version       pgtype.Int8
-	LastExecution pgtype.Timestamp
+type ExportNodesRow struct {
+	IpAddr    netip.Addr
+	SourceIds []int32
+	LastSeen  pgtype.Timestamp
 }
 
-func (q *Queries) ListAllNodes(ctx context.Context, arg ListAllNodesParams) ([]ListAllNodesRow, error) {
-	rows, err := q.db.Query(ctx, listAllNodes, arg.IpAddr, arg.Limit)
+// Every aggregated node, see ExportNodesEach for reading it without buffering
+// the result set.
+func (q *Queries) ExportNodes(ctx context.Context, arg ExportNodesParams) ([]ExportNodesRow, error) {
+	rows, err := q.db.Query(ctx, exportNodes, arg.ListID, arg.Invert)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListAllNodesRow
+	var items []ExportNodesRow
 	for rows.Next() {
-		var i ListAllNodesRow
-		if err := rows.Scan(
-			&i.IpAddr,
-			&i.SourceID,
-			&i.Version,
-			&i.LastExecution,
-		); err != nil {
+		var i ExportNodesRow
+		if err := rows.Scan(&i.IpAddr, &i.SourceIds, &i.LastSeen); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -60,50 +132,89 @@ func (q *Queries) ListAllNodes(ctx context.Context, arg ListAllNodesParams) ([]L
 	return items, nil
 }
 
-const listFilteredAllowlistNodes = `-- name: ListFilteredAllowlistNodes :many
-SELECT n.ip_addr, n.source_id, n.version, s.last_execution
-FROM nodes n
-INNER JOIN sources s ON s.id = n.source_id
-WHERE 1=1
-AND s.version < n.version 
-AND n.ip_addr > $1
-AND n.ip_addr <<= ANY (
-    SELECT a.cidr
-    FROM allowlist_entry a 
-    WHERE 1=1 
-    AND a.list_id = $3
+const listAggregatedNodes = `-- name: ListAggregatedNodes :many
+WITH page AS (
+    SELECT 
+        n.ip_addr,
+        array_agg(n.source_id ORDER BY n.source_id)::int[] AS source_ids,
+        array_agg(n.version ORDER BY n.source_id)::bigint[] AS versions,
+        array_agg(s.last_execution ORDER BY n.source_id)::timestamp[] AS last_executions,
+        MAX(GREATEST(n.last_seen, s.last_confirmed_at))::timestamp AS last_seen
+    FROM nodes n
+    INNER JOIN sources s ON s.id = n.source_id
+    WHERE 1=1
+    AND s.version < n.version 
+    AND n.ip_addr > $1
+    AND ($2::int IS NULL OR n.source_id = $2)
+    AND (
+        $3::int IS NULL
+        OR $4::boolean <> (n.ip_addr <<= ANY (
+            SELECT a.cidr
+            FROM allowlist_entry a 
+            WHERE 1=1 
+            AND a.list_id = $3
+        ))
+    )
+    GROUP BY n.ip_addr
+    ORDER BY n.ip_addr
+    LIMIT $5
 )
-ORDER BY n.ip_addr
-LIMIT $2
+SELECT p.ip_addr, p.source_ids, p.versions, p.last_executions, p.last_seen, f.first_seen::timestamp AS first_seen
+FROM page p
+LEFT JOIN LATERAL (
+    SELECT MIN(e.occurred_at) AS first_seen
+    FROM node_events e
+    WHERE 1=1
+    AND e.ip_addr = p.ip_addr
+    AND e.event = 'appeared'
+) f ON TRUE
+ORDER BY p.ip_addr
 `
 
-type ListFilteredAllowlistNodesParams struct {
-	IpAddr netip.Addr
-	Limit  int32
-	ListID int32
+type ListAggregatedNodesParams struct {
+	After      netip.Addr
+	SourceID   pgtype.Int4
+	ListID     pgtype.Int4
+	Invert     bool
+	MaxResults int32
 }
 
-type ListFilteredAllowlistNodesRow struct {
-	IpAddr        netip.Addr
-	SourceID      int32
-	Version       pgtype.Int8
-	LastExecution pgtype.Timestamp
+type ListAggregatedNodesRow struct {
+	IpAddr         netip.Addr
+	SourceIds      []int32
+	Versions       []int64
+	LastExecutions []pgtype.Timestamp
+	LastSeen       pgtype.Timestamp
+	FirstSeen      pgtype.Timestamp
 }
 
-func (q *Queries) ListFilteredAllowlistNodes(ctx context.Context, arg ListFilteredAllowlistNodesParams) ([]ListFilteredAllowlistNodesRow, error) {
-	rows, err := q.db.Query(ctx, listFilteredAllowlistNodes, arg.IpAddr, arg.Limit, arg.ListID)
+// Nodes are grouped per ip address before the page is cut so that an ip seen
+// in several sources is never split across pages. The allowlist behaves the
+// same as LookupNodes. A node was last seen no earlier than the last time its
+// source confirmed the snapshot, which an unchanged feed does without touching
+// the nodes.
+func (q *Queries) ListAggregatedNodes(ctx context.Context, arg ListAggregatedNodesParams) ([]ListAggregatedNodesRow, error) {
+	rows, err := q.db.Query(ctx, listAggregatedNodes,
+		arg.After,
+		arg.SourceID,
+		arg.ListID,
+		arg.Invert,
+		arg.MaxResults,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListFilteredAllowlistNodesRow
+	var items []ListAggregatedNodesRow
 	for rows.Next() {
-		var i ListFilteredAllowlistNodesRow
+		var i ListAggregatedNodesRow
 		if err := rows.Scan(
 			&i.IpAddr,
-			&i.SourceID,
-			&i.Version,
-			&i.LastExecution,
+			&i.SourceIds,
+			&i.Versions,
+			&i.LastExecutions,
+			&i.LastSeen,
+			&i.FirstSeen,
 		); err != nil {
 			return nil, err
 		}
@@ -115,54 +226,28 @@ func (q *Queries) ListFilteredAllowlistNodes(ctx context.Context, arg ListFilter
 	return items, nil
 }
 
-const listNodesWithoutAllowlist = `-- name: ListNodesWithoutAllowlist :many
-SELECT n.ip_addr, n.source_id, n.version, s.last_execution
+const listSourceSnapshot = `-- name: ListSourceSnapshot :many
+SELECT n.ip_addr
 FROM nodes n
 INNER JOIN sources s ON s.id = n.source_id
 WHERE 1=1
-AND s.version < n.version 
-AND n.ip_addr > $1
-AND NOT n.ip_addr <<= ANY (
-    SELECT a.cidr
-    FROM allowlist_entry a 
-    WHERE 1=1 
-    AND a.list_id = $3
-)
-ORDER BY n.ip_addr
-LIMIT $2
+AND s.version < n.version
+AND s.id = $1
 `
 
-type ListNodesWithoutAllowlistParams struct {
-	IpAddr netip.Addr
-	Limit  int32
-	ListID int32
-}
-
-type ListNodesWithoutAllowlistRow struct {
-	IpAddr        netip.Addr
-	SourceID      int32
-	Version       pgtype.Int8
-	LastExecution pgtype.Timestamp
-}
-
-func (q *Queries) ListNodesWithoutAllowlist(ctx context.Context, arg ListNodesWithoutAllowlistParams) ([]ListNodesWithoutAllowlistRow, error) {
-	rows, err := q.db.Query(ctx, listNodesWithoutAllowlist, arg.IpAddr, arg.Limit, arg.ListID)
+func (q *Queries) ListSourceSnapshot(ctx context.Context, id int32) ([]netip.Addr, error) {
+	rows, err := q.db.Query(ctx, listSourceSnapshot, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListNodesWithoutAllowlistRow
+	var items []netip.Addr
 	for rows.Next() {
-		var i ListNodesWithoutAllowlistRow
-		if err := rows.Scan(
-			&i.IpAddr,
-			&i.SourceID,
-			&i.Version,
-			&i.LastExecution,
-		); err != nil {
+		var ip_addr netip.Addr
+		if err := rows.Scan(&ip_addr); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, ip_addr)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -170,45 +255,66 @@ func (q *Queries) ListNodesWithoutAllowlist(ctx context.Context, arg ListNodesWi
 	return items, nil
 }
 
-const listSourcesNodes = `-- name: ListSourcesNodes :many
-SELECT n.ip_addr, n.source_id, n.version, s.last_execution
+const lookupNodes = `-- name: LookupNodes :many
+SELECT n.ip_addr, n.source_id, n.version, s.last_execution, GREATEST(n.last_seen, s.last_confirmed_at)::timestamp AS last_seen, f.first_seen::timestamp AS first_seen
 FROM nodes n
 INNER JOIN sources s ON s.id = n.source_id
-WHERE 1=1 
+LEFT JOIN LATERAL (
+    SELECT MIN(e.occurred_at) AS first_seen
+    FROM node_events e
+    WHERE 1=1
+    AND e.ip_addr = n.ip_addr
+    AND e.event = 'appeared'
+) f ON TRUE
+WHERE 1=1
 AND s.version < n.version 
-AND s.id = $3
-AND n.ip_addr > $1
-ORDER BY n.ip_addr
-LIMIT $2
+AND n.ip_addr = ANY($1::inet[])
+AND (
+    $2::int IS NULL
+    OR $3::boolean <> (n.ip_addr <<= ANY (
+        SELECT a.cidr
+        FROM allowlist_entry a 
+        WHERE 1=1 
+        AND a.list_id = $2
+    ))
+)
+ORDER BY n.ip_addr, n.source_id
 `
 
-type ListSourcesNodesParams struct {
-	IpAddr netip.Addr
-	Limit  int32
-	ID     int32
+type LookupNodesParams struct {
+	IpAddrs []netip.Addr
+	ListID  pgtype.Int4
+	Invert  bool
 }
 
-type ListSourcesNodesRow struct {
+type LookupNodesRow struct {
 	IpAddr        netip.Addr
 	SourceID      int32
 	Version       pgtype.Int8
 	LastExecution pgtype.Timestamp
+	LastSeen      pgtype.Timestamp
+	FirstSeen     pgtype.Timestamp
 }
 
-func (q *Queries) ListSourcesNodes(ctx context.Context, arg ListSourcesNodesParams) ([]ListSourcesNodesRow, error) {
-	rows, err := q.db.Query(ctx, listSourcesNodes, arg.IpAddr, arg.Limit, arg.ID)
+// The optional allowlist behaves the same as the listing queries, only nodes
+// inside the allowlist are returned unless `invert` is set, in which case only
+// nodes outside of it are returned.
+func (q *Queries) LookupNodes(ctx context.Context, arg LookupNodesParams) ([]LookupNodesRow, error) {
+	rows, err := q.db.Query(ctx, lookupNodes, arg.IpAddrs, arg.ListID, arg.Invert)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListSourcesNodesRow
+	var items []LookupNodesRow
 	for rows.Next() {
-		var i ListSourcesNodesRow
+		var i LookupNodesRow
 		if err := rows.Scan(
 			&i.IpAddr,
 			&i.SourceID,
 			&i.Version,
 			&i.LastExecution,
+			&i.LastSeen,
+			&i.FirstSeen,
 		); err != nil {
 			return nil, err
 		}
@@ -219,3 +325,24 @@ func (q *Queries) ListSourcesNodes(ctx context.Context, arg ListSourcesNodesPara
 	}
 	return items, nil
 }
+
+const markSupersededNodes = `-- name: MarkSupersededNodes :execrows
+UPDATE nodes n
+SET superseded_at = now()
+FROM sources s
+WHERE 1=1
+AND s.id = n.source_id
+AND n.version <= s.version
+AND n.superseded_at IS NULL
+`
+
+// Stamps nodes that are no longer part of their source's current snapshot
+// (including every node of a stopped source) so that the grace period can be
+// measured from when they were first seen as superseded.
+func (q *Queries) MarkSupersededNodes(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, markSupersededNodes)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
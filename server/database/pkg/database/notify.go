@@ -0,0 +1,19 @@
+package database
+
+import (
+	"context"
+	"strconv"
+)
+
+// SourcesChannel is notified with the source id whenever a source may have
+// become due sooner than the ingester expects, so that it can wake up early.
+const SourcesChannel = "prophet_sources"
+
+const notifySourceChanged = `SELECT pg_notify($1, $2)`
+
+// NotifySourceChanged queues a notification on SourcesChannel. Inside a
+// transaction it is only delivered once the transaction commits.
+func (q *Queries) NotifySourceChanged(ctx context.Context, sourceId int32) error {
+	_, err := q.db.Exec(ctx, notifySourceChanged, SourcesChannel, strconv.Itoa(int(sourceId)))
+	return err
+}
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"context"
+)
+
+// SchemaVersion is the version of the newest migration in the migrations
+// directory. It has to be bumped with every new migration so that services
+// can tell whether the database has been migrated far enough for them.
+const SchemaVersion = 17
+
+const getSchemaVersion = `SELECT version, dirty FROM schema_migrations LIMIT 1`
+
+// GetSchemaVersion reads the version golang-migrate recorded for the last
+// migration it applied and whether that migration failed part way through.
+func (q *Queries) GetSchemaVersion(ctx context.Context) (int64, bool, error) {
+	var version int64
+	var dirty bool
+
+	row := q.db.QueryRow(ctx, getSchemaVersion)
+	err := row.Scan(&version, &dirty)
+
+	return version, dirty, err
+}
@@ -11,22 +11,112 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimEligableSources = `-- name: ClaimEligableSources :many
+UPDATE sources
+SET lease_owner = $1, lease_expires_at = now() + $2::interval
+WHERE id IN (
+    SELECT c.id
+    FROM sources c
+    WHERE 1=1
+    AND c.running = TRUE
+    AND (c.lease_expires_at IS NULL OR c.lease_expires_at < now())
+    AND (
+        (
+            (c.last_execution IS NULL OR c.last_execution + c.period < now())
+            AND (c.paused_until IS NULL OR c.paused_until < now())
+        )
+        OR EXISTS (
+            SELECT 1 
+            FROM ingestion_runs r 
+            WHERE r.source_id = c.id 
+            AND r.status IN ('pending', 'running')
+        )
+    )
+    ORDER BY c.last_execution NULLS FIRST
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at
+`
+
+type ClaimEligableSourcesParams struct {
+	LeaseOwner    pgtype.Text
+	LeaseDuration pgtype.Interval
+	MaxSources    int32
+}
+
+// Atomically leases up to `max_sources` eligable sources to a single ingester.
+// Rows locked by a concurrent claim are skipped so that two ingesters never
+// receive the same source, and a lease left behind by an ingester that died
+// becomes claimable again once it expires. Sources paused by the circuit
+// breaker are skipped until their cooldown has elapsed, unless a run has been
+// requested for them which also makes them due ahead of their period.
+func (q *Queries) ClaimEligableSources(ctx context.Context, arg ClaimEligableSourcesParams) ([]Source, error) {
+	rows, err := q.db.Query(ctx, claimEligableSources, arg.LeaseOwner, arg.LeaseDuration, arg.MaxSources)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Source
+	for rows.Next() {
+		var i Source
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.Period,
+			&i.LastExecution,
+			&i.Version,
+			&i.Running,
+			&i.Format,
+			&i.FormatOptions,
+			&i.LastSuccess,
+			&i.LastError,
+			&i.ConsecutiveFailures,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.ContentHash,
+			&i.PausedUntil,
+			&i.Credentials,
+			&i.Generation,
+			&i.LastConfirmedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createSource = `-- name: CreateSource :one
-INSERT INTO sources (name, url, period) 
-VALUES ($1, $2, $3) 
-ON CONFLICT(name) 
-DO NOTHING
-RETURNING id, name, url, period, last_execution, version, running
+INSERT INTO sources (name, url, period, format, format_options, credentials) 
+VALUES ($1, $2, $3, $4, $5, $6) 
+RETURNING id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at
 `
 
 type CreateSourceParams struct {
-	Name   string
-	Url    string
-	Period pgtype.Interval
+	Name          string
+	Url           string
+	Period        pgtype.Interval
+	Format        string
+	FormatOptions []byte
+	Credentials   []byte
 }
 
 func (q *Queries) CreateSource(ctx context.Context, arg CreateSourceParams) (Source, error) {
-	row := q.db.QueryRow(ctx, createSource, arg.Name, arg.Url, arg.Period)
+	row := q.db.QueryRow(ctx, createSource,
+		arg.Name,
+		arg.Url,
+		arg.Period,
+		arg.Format,
+		arg.FormatOptions,
+		arg.Credentials,
+	)
 	var i Source
 	err := row.Scan(
 		&i.ID,
@@ -36,12 +126,62 @@ func (q *Queries) CreateSource(ctx context.Context, arg CreateSourceParams) (Sou
 		&i.LastExecution,
 		&i.Version,
 		&i.Running,
+		&i.Format,
+		&i.FormatOptions,
+		&i.LastSuccess,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ContentHash,
+		&i.PausedUntil,
+		&i.Credentials,
+		&i.Generation,
+		&i.LastConfirmedAt,
+	)
+	return i, err
+}
+
+const deleteSource = `-- name: DeleteSource :one
+DELETE FROM sources
+WHERE id = $1
+RETURNING id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at
+`
+
+// The source's nodes and their history are removed by the foreign keys.
+func (q *Queries) DeleteSource(ctx context.Context, id int32) (Source, error) {
+	row := q.db.QueryRow(ctx, deleteSource, id)
+	var i Source
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.Period,
+		&i.LastExecution,
+		&i.Version,
+		&i.Running,
+		&i.Format,
+		&i.FormatOptions,
+		&i.LastSuccess,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ContentHash,
+		&i.PausedUntil,
+		&i.Credentials,
+		&i.Generation,
+		&i.LastConfirmedAt,
 	)
 	return i, err
 }
 
 const getSource = `-- name: GetSource :one
-SELECT id, name, url, period, last_execution, version, running 
+SELECT id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at 
 FROM sources
 WHERE 1=1
 AND id = $1
@@ -59,12 +199,26 @@ func (q *Queries) GetSource(ctx context.Context, id int32) (Source, error) {
 		&i.LastExecution,
 		&i.Version,
 		&i.Running,
+		&i.Format,
+		&i.FormatOptions,
+		&i.LastSuccess,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ContentHash,
+		&i.PausedUntil,
+		&i.Credentials,
+		&i.Generation,
+		&i.LastConfirmedAt,
 	)
 	return i, err
 }
 
 const listAllSources = `-- name: ListAllSources :many
-SELECT id, name, url, period, last_execution, version, running 
+SELECT id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at 
 FROM sources
 WHERE 1=1
 AND id > $1
@@ -94,6 +248,20 @@ func (q *Queries) ListAllSources(ctx context.Context, arg ListAllSourcesParams)
 			&i.LastExecution,
 			&i.Version,
 			&i.Running,
+			&i.Format,
+			&i.FormatOptions,
+			&i.LastSuccess,
+			&i.LastError,
+			&i.ConsecutiveFailures,
+			&i.LeaseOwner,
+			&i.LeaseExpiresAt,
+			&i.Etag,
+			&i.LastModified,
+			&i.ContentHash,
+			&i.PausedUntil,
+			&i.Credentials,
+			&i.Generation,
+			&i.LastConfirmedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -105,31 +273,52 @@ func (q *Queries) ListAllSources(ctx context.Context, arg ListAllSourcesParams)
 	return items, nil
 }
 
-const listEligableSources = `-- name: ListEligableSources :many
-SELECT id, name, url, period, last_execution, version, running
+const listSourceFreshness = `-- name: ListSourceFreshness :many
+SELECT 
+    id,
+    name,
+    running,
+    last_execution,
+    last_success,
+    consecutive_failures,
+    lease_owner,
+    paused_until,
+    (COALESCE(running, FALSE) AND (last_success IS NULL OR last_success < now() - period * 2))::boolean AS stale
 FROM sources
-WHERE 1=1
-AND (last_execution IS NULL OR last_execution + period < now())
-AND running = TRUE
+ORDER BY id
 `
 
-func (q *Queries) ListEligableSources(ctx context.Context) ([]Source, error) {
-	rows, err := q.db.Query(ctx, listEligableSources)
+type ListSourceFreshnessRow struct {
+	ID                  int32
+	Name                string
+	Running             pgtype.Bool
+	LastExecution       pgtype.Timestamp
+	LastSuccess         pgtype.Timestamp
+	ConsecutiveFailures int32
+	LeaseOwner          pgtype.Text
+	PausedUntil         pgtype.Timestamp
+	Stale               bool
+}
+
+func (q *Queries) ListSourceFreshness(ctx context.Context) ([]ListSourceFreshnessRow, error) {
+	rows, err := q.db.Query(ctx, listSourceFreshness)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Source
+	var items []ListSourceFreshnessRow
 	for rows.Next() {
-		var i Source
+		var i ListSourceFreshnessRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
-			&i.Url,
-			&i.Period,
-			&i.LastExecution,
-			&i.Version,
 			&i.Running,
+			&i.LastExecution,
+			&i.LastSuccess,
+			&i.ConsecutiveFailures,
+			&i.LeaseOwner,
+			&i.PausedUntil,
+			&i.Stale,
 		); err != nil {
 			return nil, err
 		}
@@ -141,15 +330,336 @@ func (q *Queries) ListEligableSources(ctx context.Context) ([]Source, error) {
 	return items, nil
 }
 
+const nextSourceDueIn = `-- name: NextSourceDueIn :one
+SELECT EXTRACT(EPOCH FROM (d.due_at - LOCALTIMESTAMP))::float8 AS due_in
+FROM (
+    SELECT 
+        CASE 
+            WHEN EXISTS (
+                SELECT 1 
+                FROM ingestion_runs r 
+                WHERE r.source_id = c.id 
+                AND r.status IN ('pending', 'running')
+            ) THEN COALESCE(c.lease_expires_at, LOCALTIMESTAMP)
+            ELSE GREATEST(
+                COALESCE(c.last_execution + c.period, LOCALTIMESTAMP),
+                COALESCE(c.paused_until, LOCALTIMESTAMP),
+                COALESCE(c.lease_expires_at, LOCALTIMESTAMP)
+            )
+        END AS due_at
+    FROM sources c
+    WHERE c.running = TRUE
+) d
+ORDER BY d.due_at
+LIMIT 1
+`
+
+// Seconds until the next running source can be claimed, zero or less if one
+// already can. Fails with no rows when there are no running sources.
+func (q *Queries) NextSourceDueIn(ctx context.Context) (float64, error) {
+	row := q.db.QueryRow(ctx, nextSourceDueIn)
+	var due_in float64
+	err := row.Scan(&due_in)
+	return due_in, err
+}
+
 const prepareExecution = `-- name: PrepareExecution :one
 UPDATE sources
-SET last_execution = now(), version = version + 1
+SET last_execution = now()
+WHERE 1=1
+AND id = $1
+AND lease_owner = $2
+RETURNING id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at
+`
+
+type PrepareExecutionParams struct {
+	ID         int32
+	LeaseOwner pgtype.Text
+}
+
+func (q *Queries) PrepareExecution(ctx context.Context, arg PrepareExecutionParams) (Source, error) {
+	row := q.db.QueryRow(ctx, prepareExecution, arg.ID, arg.LeaseOwner)
+	var i Source
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.Period,
+		&i.LastExecution,
+		&i.Version,
+		&i.Running,
+		&i.Format,
+		&i.FormatOptions,
+		&i.LastSuccess,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ContentHash,
+		&i.PausedUntil,
+		&i.Credentials,
+		&i.Generation,
+		&i.LastConfirmedAt,
+	)
+	return i, err
+}
+
+const publishExecution = `-- name: PublishExecution :one
+UPDATE sources
+SET 
+    version = version + 1,
+    last_confirmed_at = now(),
+    etag = $1,
+    last_modified = $2,
+    content_hash = $3
+WHERE 1=1
+AND id = $4
+AND version = $5
+AND generation = $6
+AND running = TRUE
+RETURNING id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at
+`
+
+type PublishExecutionParams struct {
+	Etag         pgtype.Text
+	LastModified pgtype.Text
+	ContentHash  []byte
+	ID           int32
+	Version      pgtype.Int8
+	Generation   int64
+}
+
+// Makes the nodes written with version + 2 visible and hides the previous
+// snapshot. Fails with no rows if the source was stopped, published by
+// someone else or given a new feed since `version` and `generation` were read.
+// The validators of the feed that produced the snapshot are stored so the next
+// fetch can be conditional.
+func (q *Queries) PublishExecution(ctx context.Context, arg PublishExecutionParams) (Source, error) {
+	row := q.db.QueryRow(ctx, publishExecution,
+		arg.Etag,
+		arg.LastModified,
+		arg.ContentHash,
+		arg.ID,
+		arg.Version,
+		arg.Generation,
+	)
+	var i Source
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.Period,
+		&i.LastExecution,
+		&i.Version,
+		&i.Running,
+		&i.Format,
+		&i.FormatOptions,
+		&i.LastSuccess,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ContentHash,
+		&i.PausedUntil,
+		&i.Credentials,
+		&i.Generation,
+		&i.LastConfirmedAt,
+	)
+	return i, err
+}
+
+const recordSourceFailure = `-- name: RecordSourceFailure :one
+UPDATE sources
+SET 
+    last_error = $1, 
+    consecutive_failures = consecutive_failures + 1,
+    paused_until = CASE 
+        WHEN consecutive_failures + 1 >= $2::int THEN now() + $3::interval 
+        ELSE paused_until 
+    END
+WHERE id = $4
+RETURNING id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at
+`
+
+type RecordSourceFailureParams struct {
+	LastError        pgtype.Text
+	FailureThreshold int32
+	Cooldown         pgtype.Interval
+	ID               int32
+}
+
+// Pauses the source for `cooldown` once it has failed `failure_threshold`
+// times in a row.
+func (q *Queries) RecordSourceFailure(ctx context.Context, arg RecordSourceFailureParams) (Source, error) {
+	row := q.db.QueryRow(ctx, recordSourceFailure,
+		arg.LastError,
+		arg.FailureThreshold,
+		arg.Cooldown,
+		arg.ID,
+	)
+	var i Source
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.Period,
+		&i.LastExecution,
+		&i.Version,
+		&i.Running,
+		&i.Format,
+		&i.FormatOptions,
+		&i.LastSuccess,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ContentHash,
+		&i.PausedUntil,
+		&i.Credentials,
+		&i.Generation,
+		&i.LastConfirmedAt,
+	)
+	return i, err
+}
+
+const recordSourceSuccess = `-- name: RecordSourceSuccess :one
+UPDATE sources
+SET last_success = now(), last_error = NULL, consecutive_failures = 0, paused_until = NULL
+WHERE id = $1
+RETURNING id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at
+`
+
+func (q *Queries) RecordSourceSuccess(ctx context.Context, id int32) (Source, error) {
+	row := q.db.QueryRow(ctx, recordSourceSuccess, id)
+	var i Source
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.Period,
+		&i.LastExecution,
+		&i.Version,
+		&i.Running,
+		&i.Format,
+		&i.FormatOptions,
+		&i.LastSuccess,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ContentHash,
+		&i.PausedUntil,
+		&i.Credentials,
+		&i.Generation,
+		&i.LastConfirmedAt,
+	)
+	return i, err
+}
+
+const recordSourceUnchanged = `-- name: RecordSourceUnchanged :execrows
+UPDATE sources
+SET 
+    etag = COALESCE($1, etag),
+    last_modified = COALESCE($2, last_modified),
+    last_confirmed_at = now()
+WHERE 1=1
+AND id = $3
+AND version = $4
+AND generation = $5
+AND running = TRUE
+`
+
+type RecordSourceUnchangedParams struct {
+	Etag         pgtype.Text
+	LastModified pgtype.Text
+	ID           int32
+	Version      pgtype.Int8
+	Generation   int64
+}
+
+// Keeps the published snapshot when the feed has not changed since it was
+// fetched, only refreshing validators the server sent again and when the feed
+// last confirmed the snapshot. Affects no rows if the source was stopped,
+// published by someone else or given a new feed since `version` and
+// `generation` were read.
+func (q *Queries) RecordSourceUnchanged(ctx context.Context, arg RecordSourceUnchangedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, recordSourceUnchanged,
+		arg.Etag,
+		arg.LastModified,
+		arg.ID,
+		arg.Version,
+		arg.Generation,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const releaseSourceLease = `-- name: ReleaseSourceLease :exec
+UPDATE sources
+SET lease_owner = NULL, lease_expires_at = NULL
+WHERE 1=1
+AND id = $1
+AND lease_owner = $2
+`
+
+type ReleaseSourceLeaseParams struct {
+	ID         int32
+	LeaseOwner pgtype.Text
+}
+
+func (q *Queries) ReleaseSourceLease(ctx context.Context, arg ReleaseSourceLeaseParams) error {
+	_, err := q.db.Exec(ctx, releaseSourceLease, arg.ID, arg.LeaseOwner)
+	return err
+}
+
+const renewSourceLease = `-- name: RenewSourceLease :execrows
+UPDATE sources
+SET lease_expires_at = now() + $1::interval
+WHERE 1=1
+AND id = $2
+AND lease_owner = $3
+`
+
+type RenewSourceLeaseParams struct {
+	LeaseDuration pgtype.Interval
+	ID            int32
+	LeaseOwner    pgtype.Text
+}
+
+func (q *Queries) RenewSourceLease(ctx context.Context, arg RenewSourceLeaseParams) (int64, error) {
+	result, err := q.db.Exec(ctx, renewSourceLease, arg.LeaseDuration, arg.ID, arg.LeaseOwner)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setSourceCredentials = `-- name: SetSourceCredentials :one
+UPDATE sources
+SET credentials = $2
 WHERE id = $1
-RETURNING id, name, url, period, last_execution, version, running
+RETURNING id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at
 `
 
-func (q *Queries) PrepareExecution(ctx context.Context, id int32) (Source, error) {
-	row := q.db.QueryRow(ctx, prepareExecution, id)
+type SetSourceCredentialsParams struct {
+	ID          int32
+	Credentials []byte
+}
+
+// Credentials are sealed with the source's id, so a new source stores them
+// once its id is known.
+func (q *Queries) SetSourceCredentials(ctx context.Context, arg SetSourceCredentialsParams) (Source, error) {
+	row := q.db.QueryRow(ctx, setSourceCredentials, arg.ID, arg.Credentials)
 	var i Source
 	err := row.Scan(
 		&i.ID,
@@ -159,17 +669,33 @@ func (q *Queries) PrepareExecution(ctx context.Context, id int32) (Source, error
 		&i.LastExecution,
 		&i.Version,
 		&i.Running,
+		&i.Format,
+		&i.FormatOptions,
+		&i.LastSuccess,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ContentHash,
+		&i.PausedUntil,
+		&i.Credentials,
+		&i.Generation,
+		&i.LastConfirmedAt,
 	)
 	return i, err
 }
 
 const startSource = `-- name: StartSource :one
 UPDATE sources 
-SET running = TRUE
+SET running = TRUE, paused_until = NULL
 WHERE id = $1
-RETURNING id, name, url, period, last_execution, version, running
+RETURNING id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at
 `
 
+// Also lifts a pause left by the circuit breaker. The failure count is kept so
+// that the next failure pauses the source again straight away.
 func (q *Queries) StartSource(ctx context.Context, id int32) (Source, error) {
 	row := q.db.QueryRow(ctx, startSource, id)
 	var i Source
@@ -181,17 +707,42 @@ func (q *Queries) StartSource(ctx context.Context, id int32) (Source, error) {
 		&i.LastExecution,
 		&i.Version,
 		&i.Running,
+		&i.Format,
+		&i.FormatOptions,
+		&i.LastSuccess,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ContentHash,
+		&i.PausedUntil,
+		&i.Credentials,
+		&i.Generation,
+		&i.LastConfirmedAt,
 	)
 	return i, err
 }
 
 const stopSource = `-- name: StopSource :one
+WITH disappeared AS (
+    INSERT INTO node_events (ip_addr, source_id, event)
+    SELECT n.ip_addr, n.source_id, 'disappeared'
+    FROM nodes n
+    INNER JOIN sources s ON s.id = n.source_id
+    WHERE 1=1
+    AND s.version < n.version
+    AND s.id = $1
+)
 UPDATE sources 
-SET running = FALSE, version = version + 1
-WHERE id = $1
-RETURNING id, name, url, period, last_execution, version, running
+SET running = FALSE, version = version + 1, etag = NULL, last_modified = NULL, content_hash = NULL
+WHERE sources.id = $1
+RETURNING id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at
 `
 
+// Hides the published snapshot. The feed validators are cleared as well so the
+// first run after the source is started again publishes a fresh snapshot.
 func (q *Queries) StopSource(ctx context.Context, id int32) (Source, error) {
 	row := q.db.QueryRow(ctx, stopSource, id)
 	var i Source
@@ -203,6 +754,98 @@ func (q *Queries) StopSource(ctx context.Context, id int32) (Source, error) {
 		&i.LastExecution,
 		&i.Version,
 		&i.Running,
+		&i.Format,
+		&i.FormatOptions,
+		&i.LastSuccess,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ContentHash,
+		&i.PausedUntil,
+		&i.Credentials,
+		&i.Generation,
+		&i.LastConfirmedAt,
+	)
+	return i, err
+}
+
+const updateSource = `-- name: UpdateSource :one
+UPDATE sources
+SET 
+    name = COALESCE($1, name),
+    url = COALESCE($2, url),
+    period = COALESCE($3, period),
+    format = COALESCE($4, format),
+    format_options = COALESCE($5, format_options),
+    credentials = CASE WHEN $6::boolean THEN $7 ELSE credentials END,
+    last_execution = CASE WHEN $8::boolean THEN NULL ELSE last_execution END,
+    etag = CASE WHEN $8::boolean THEN NULL ELSE etag END,
+    last_modified = CASE WHEN $8::boolean THEN NULL ELSE last_modified END,
+    content_hash = CASE WHEN $8::boolean THEN NULL ELSE content_hash END,
+    consecutive_failures = CASE WHEN $8::boolean THEN 0 ELSE consecutive_failures END,
+    last_error = CASE WHEN $8::boolean THEN NULL ELSE last_error END,
+    paused_until = CASE WHEN $8::boolean THEN NULL ELSE paused_until END,
+    generation = CASE WHEN $8::boolean THEN generation + 1 ELSE generation END
+WHERE id = $9
+RETURNING id, name, url, period, last_execution, version, running, format, format_options, last_success, last_error, consecutive_failures, lease_owner, lease_expires_at, etag, last_modified, content_hash, paused_until, credentials, generation, last_confirmed_at
+`
+
+type UpdateSourceParams struct {
+	Name               pgtype.Text
+	Url                pgtype.Text
+	Period             pgtype.Interval
+	Format             pgtype.Text
+	FormatOptions      []byte
+	ReplaceCredentials bool
+	Credentials        []byte
+	FeedChanged        bool
+	ID                 int32
+}
+
+// Only changes the fields that are given, credentials are replaced (or
+// cleared) when `replace_credentials` is set. When `feed_changed` is set the
+// source is made due straight away, the validators, failures and pause left by
+// the previous feed are forgotten and the generation moves on so that an ingestion
+// of the previous feed that is still running can not publish. The published
+// snapshot stays visible until the next ingestion replaces it.
+func (q *Queries) UpdateSource(ctx context.Context, arg UpdateSourceParams) (Source, error) {
+	row := q.db.QueryRow(ctx, updateSource,
+		arg.Name,
+		arg.Url,
+		arg.Period,
+		arg.Format,
+		arg.FormatOptions,
+		arg.ReplaceCredentials,
+		arg.Credentials,
+		arg.FeedChanged,
+		arg.ID,
+	)
+	var i Source
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.Period,
+		&i.LastExecution,
+		&i.Version,
+		&i.Running,
+		&i.Format,
+		&i.FormatOptions,
+		&i.LastSuccess,
+		&i.LastError,
+		&i.ConsecutiveFailures,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.Etag,
+		&i.LastModified,
+		&i.ContentHash,
+		&i.PausedUntil,
+		&i.Credentials,
+		&i.Generation,
+		&i.LastConfirmedAt,
 	)
 	return i, err
 }
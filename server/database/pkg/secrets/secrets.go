@@ -0,0 +1,138 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the length of the key in bytes, the box uses AES-256-GCM.
+const KeySize = 32
+
+// version prefixes every sealed value so that the format can change later.
+// Values sealed with version 1 did not bind any additional data and can
+// still be opened, they are sealed again with the current version the next
+// time they are written.
+const (
+	versionUnbound byte = 1
+	version        byte = 2
+)
+
+var (
+	ErrInvalidKey = errors.New("Secrets key must be 32 bytes")
+	ErrMalformed  = errors.New("Sealed value is malformed")
+)
+
+// Box seals values stored in the database with the server side key and opens
+// them again. Sealing the same value twice gives different results.
+type Box struct {
+	aead cipher.AEAD
+}
+
+func NewBox(key []byte) (*Box, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Box{aead: aead}, nil
+}
+
+// Seal encrypts plaintext as version || nonce || ciphertext. The additional
+// data is authenticated but not stored, Open fails unless it is given the same.
+func (b *Box) Seal(plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := append([]byte{version}, nonce...)
+	return b.aead.Seal(sealed, nonce, plaintext, additionalData), nil
+}
+
+// Open decrypts a value produced by Seal, failing if it was sealed with
+// another key or additional data or tampered with.
+func (b *Box) Open(sealed, additionalData []byte) ([]byte, error) {
+	nonceSize := b.aead.NonceSize()
+	if len(sealed) < 1+nonceSize {
+		return nil, ErrMalformed
+	}
+
+	switch sealed[0] {
+	case version:
+	case versionUnbound:
+		additionalData = nil
+	default:
+		return nil, ErrMalformed
+	}
+
+	nonce, ciphertext := sealed[1:1+nonceSize], sealed[1+nonceSize:]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	return plaintext, nil
+}
+
+type BasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// SourceCredentials are the request headers and credentials sent with every
+// fetch of a source's feed.
+type SourceCredentials struct {
+	Headers     map[string]string `json:"headers,omitempty"`
+	BearerToken string            `json:"bearer_token,omitempty"`
+	BasicAuth   *BasicAuth        `json:"basic_auth,omitempty"`
+}
+
+func (c SourceCredentials) IsEmpty() bool {
+	return len(c.Headers) == 0 && c.BearerToken == "" && c.BasicAuth == nil
+}
+
+// SealCredentials encodes and seals the credentials for the sources table.
+// They are bound to the source's id so that they can not be opened as the
+// credentials of another source if copied to its row.
+func (b *Box) SealCredentials(sourceId int32, credentials SourceCredentials) ([]byte, error) {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Seal(plaintext, sourceAdditionalData(sourceId))
+}
+
+// OpenCredentials reverses SealCredentials.
+func (b *Box) OpenCredentials(sourceId int32, sealed []byte) (SourceCredentials, error) {
+	plaintext, err := b.Open(sealed, sourceAdditionalData(sourceId))
+	if err != nil {
+		return SourceCredentials{}, err
+	}
+
+	var credentials SourceCredentials
+	err = json.Unmarshal(plaintext, &credentials)
+	if err != nil {
+		return SourceCredentials{}, err
+	}
+
+	return credentials, nil
+}
+
+func sourceAdditionalData(sourceId int32) []byte {
+	return fmt.Appendf(nil, "sources/%d", sourceId)
+}
@@ -0,0 +1,105 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newTestBox(t *testing.T) *Box {
+	t.Helper()
+
+	box, err := NewBox(bytes.Repeat([]byte{7}, KeySize))
+	if err != nil {
+		t.Fatalf("NewBox() error = %v", err)
+	}
+
+	return box
+}
+
+func TestCredentialsRoundTrip(t *testing.T) {
+	box := newTestBox(t)
+	credentials := SourceCredentials{
+		Headers:   map[string]string{"X-Api-Key": "secret"},
+		BasicAuth: &BasicAuth{Username: "user", Password: "pass"},
+	}
+
+	sealed, err := box.SealCredentials(3, credentials)
+	if err != nil {
+		t.Fatalf("SealCredentials() error = %v", err)
+	}
+
+	got, err := box.OpenCredentials(3, sealed)
+	if err != nil {
+		t.Fatalf("OpenCredentials() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, credentials) {
+		t.Errorf("OpenCredentials() = %+v, want %+v", got, credentials)
+	}
+}
+
+func TestOpenCredentialsOfAnotherSource(t *testing.T) {
+	box := newTestBox(t)
+
+	sealed, err := box.SealCredentials(3, SourceCredentials{BearerToken: "token"})
+	if err != nil {
+		t.Fatalf("SealCredentials() error = %v", err)
+	}
+
+	_, err = box.OpenCredentials(4, sealed)
+	if !errors.Is(err, ErrMalformed) {
+		t.Errorf("OpenCredentials() error = %v, want %v", err, ErrMalformed)
+	}
+}
+
+func TestOpenUnboundVersion(t *testing.T) {
+	box := newTestBox(t)
+
+	sealed, err := box.Seal([]byte(`{"bearer_token":"token"}`), nil)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+	sealed[0] = versionUnbound
+
+	got, err := box.OpenCredentials(4, sealed)
+	if err != nil {
+		t.Fatalf("OpenCredentials() error = %v", err)
+	}
+
+	if got.BearerToken != "token" {
+		t.Errorf("OpenCredentials() = %+v, want the bearer token", got)
+	}
+}
+
+func TestOpenMalformed(t *testing.T) {
+	box := newTestBox(t)
+
+	sealed, err := box.Seal([]byte("value"), []byte("data"))
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+
+	tampered := bytes.Clone(sealed)
+	tampered[len(tampered)-1] ^= 1
+
+	unknownVersion := bytes.Clone(sealed)
+	unknownVersion[0] = 9
+
+	tests := map[string][]byte{
+		"empty":           nil,
+		"short":           sealed[:5],
+		"tampered":        tampered,
+		"unknown version": unknownVersion,
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := box.Open(value, []byte("data"))
+			if !errors.Is(err, ErrMalformed) {
+				t.Errorf("Open() error = %v, want %v", err, ErrMalformed)
+			}
+		})
+	}
+}
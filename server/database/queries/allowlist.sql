@@ -35,8 +35,9 @@ ON CONFLICT (cidr, list_id)
 DO NOTHING
 RETURNING *;
 
--- name: RemoveFromAllowlist :exec
+-- name: RemoveFromAllowlist :one
 DELETE FROM allowlist_entry 
 WHERE 1=1
 AND id = $1 
-AND list_id = $2;
+AND list_id = $2
+RETURNING *;
@@ -0,0 +1,71 @@
+-- name: GetActiveApiKeyByHash :one
+SELECT *
+FROM api_keys
+WHERE 1=1
+AND key_hash = $1
+AND revoked_at IS NULL
+LIMIT 1;
+
+-- name: TouchApiKey :exec
+UPDATE api_keys
+SET last_used_at = now()
+WHERE 1=1
+AND id = $1
+AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
+
+-- name: ListApiKeys :many
+SELECT *
+FROM api_keys
+WHERE 1=1
+AND id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: CreateApiKey :one
+INSERT INTO api_keys (name, prefix, key_hash, scopes)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: RevokeApiKey :one
+UPDATE api_keys
+SET revoked_at = COALESCE(revoked_at, now())
+WHERE 1=1
+AND id = $1
+RETURNING *;
+
+-- name: RotateApiKey :one
+WITH revoked AS (
+    UPDATE api_keys
+    SET revoked_at = now()
+    WHERE 1=1
+    AND api_keys.id = sqlc.arg(id)
+    AND api_keys.revoked_at IS NULL
+    RETURNING api_keys.name, api_keys.scopes
+)
+INSERT INTO api_keys (name, prefix, key_hash, scopes)
+SELECT revoked.name, sqlc.arg(prefix), sqlc.arg(key_hash), revoked.scopes
+FROM revoked
+RETURNING *;
+
+-- name: LockApiKeyBootstrap :exec
+-- Held until the end of the transaction so that concurrent bootstraps check for
+-- an active key one after the other.
+SELECT pg_advisory_xact_lock(hashtext('prophet_api_key_bootstrap'));
+
+-- name: CreateBootstrapApiKey :one
+-- Fails with no rows if an active key already exists.
+INSERT INTO api_keys (name, prefix, key_hash, scopes)
+SELECT sqlc.arg(name), sqlc.arg(prefix), sqlc.arg(key_hash), sqlc.arg(scopes)::text[]
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM api_keys
+    WHERE 1=1
+    AND revoked_at IS NULL
+)
+RETURNING *;
+
+-- name: GetApiKey :one
+SELECT *
+FROM api_keys
+WHERE 1=1
+AND id = $1;
@@ -0,0 +1,16 @@
+-- name: InsertAuditLog :exec
+INSERT INTO audit_log (actor, actor_key_id, request_id, operation, resource_type, resource_id, before, after)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListAuditLog :many
+SELECT *
+FROM audit_log
+WHERE 1=1
+AND id < sqlc.arg(before_id)
+AND (sqlc.narg(actor)::text IS NULL OR actor = sqlc.narg(actor))
+AND (sqlc.narg(resource_type)::text IS NULL OR resource_type = sqlc.narg(resource_type))
+AND (sqlc.narg(resource_id)::text IS NULL OR resource_id = sqlc.narg(resource_id))
+AND (sqlc.narg(since)::timestamp IS NULL OR occurred_at >= sqlc.narg(since))
+AND (sqlc.narg(until)::timestamp IS NULL OR occurred_at < sqlc.narg(until))
+ORDER BY id DESC
+LIMIT sqlc.arg(max_results);
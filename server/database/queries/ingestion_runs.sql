@@ -0,0 +1,70 @@
+-- name: GetPendingIngestionRun :one
+SELECT *
+FROM ingestion_runs
+WHERE 1=1
+AND source_id = $1
+AND status = 'pending'
+ORDER BY id
+LIMIT 1;
+
+-- name: CreateIngestionRun :one
+INSERT INTO ingestion_runs (source_id)
+VALUES ($1)
+RETURNING *;
+
+-- name: ListSourceIngestionRuns :many
+SELECT *
+FROM ingestion_runs
+WHERE 1=1
+AND source_id = sqlc.arg(source_id)
+AND id < sqlc.arg(before_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: GetIngestionRun :one
+SELECT *
+FROM ingestion_runs
+WHERE 1=1
+AND id = $1;
+
+-- name: StartRequestedIngestionRuns :many
+-- Marks every run requested for the source as started by the ingestion that
+-- holds its lease. Runs still marked running belong to an ingestion that was
+-- cancelled or died, they are taken over as well.
+UPDATE ingestion_runs
+SET status = 'running', started_at = now()
+WHERE 1=1
+AND source_id = $1
+AND status IN ('pending', 'running')
+RETURNING id;
+
+-- name: StartScheduledIngestionRun :one
+INSERT INTO ingestion_runs (source_id, trigger, status, started_at)
+VALUES ($1, 'scheduled', 'running', now())
+RETURNING id;
+
+-- name: FinishIngestionRuns :exec
+-- `version` is the source version visible once the ingestion finished.
+UPDATE ingestion_runs
+SET 
+    status = sqlc.arg(status), 
+    error = sqlc.narg(error), 
+    finished_at = now(),
+    version = sqlc.arg(version)::bigint,
+    http_status = sqlc.narg(http_status),
+    bytes_fetched = sqlc.arg(bytes_fetched)::bigint,
+    rows_parsed = sqlc.arg(rows_parsed)::int,
+    rows_rejected = sqlc.arg(rows_rejected)::int,
+    nodes_added = sqlc.arg(nodes_added)::int,
+    nodes_removed = sqlc.arg(nodes_removed)::int
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: DeleteExpiredIngestionRuns :execrows
+DELETE FROM ingestion_runs
+WHERE id IN (
+    SELECT r.id
+    FROM ingestion_runs r
+    WHERE 1=1
+    AND r.finished_at < now() - sqlc.arg(retention)::interval
+    LIMIT sqlc.arg(batch_size)
+);
@@ -0,0 +1,13 @@
+-- name: InsertNodeEvents :copyfrom
+INSERT INTO node_events (ip_addr, source_id, event) 
+VALUES ($1, $2, $3);
+
+-- name: ListNodeEvents :many
+SELECT *
+FROM node_events
+WHERE 1=1
+AND ip_addr = $1
+AND id > $2
+AND (sqlc.narg(source_id)::int IS NULL OR source_id = sqlc.narg(source_id))
+ORDER BY id
+LIMIT $3;
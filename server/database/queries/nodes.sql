@@ -1,60 +1,158 @@
--- name: ListAllNodes :many
-SELECT n.ip_addr, n.source_id, n.version, s.last_execution
+-- name: ListAggregatedNodes :many
+-- Nodes are grouped per ip address before the page is cut so that an ip seen
+-- in several sources is never split across pages. The allowlist behaves the
+-- same as LookupNodes. A node was last seen no earlier than the last time its
+-- source confirmed the snapshot, which an unchanged feed does without touching
+-- the nodes.
+WITH page AS (
+    SELECT 
+        n.ip_addr,
+        array_agg(n.source_id ORDER BY n.source_id)::int[] AS source_ids,
+        array_agg(n.version ORDER BY n.source_id)::bigint[] AS versions,
+        array_agg(s.last_execution ORDER BY n.source_id)::timestamp[] AS last_executions,
+        MAX(GREATEST(n.last_seen, s.last_confirmed_at))::timestamp AS last_seen
+    FROM nodes n
+    INNER JOIN sources s ON s.id = n.source_id
+    WHERE 1=1
+    AND s.version < n.version 
+    AND n.ip_addr > sqlc.arg(after)
+    AND (sqlc.narg(source_id)::int IS NULL OR n.source_id = sqlc.narg(source_id))
+    AND (
+        sqlc.narg(list_id)::int IS NULL
+        OR sqlc.arg(invert)::boolean <> (n.ip_addr <<= ANY (
+            SELECT a.cidr
+            FROM allowlist_entry a 
+            WHERE 1=1 
+            AND a.list_id = sqlc.narg(list_id)
+        ))
+    )
+    GROUP BY n.ip_addr
+    ORDER BY n.ip_addr
+    LIMIT sqlc.arg(max_results)
+)
+SELECT p.ip_addr, p.source_ids, p.versions, p.last_executions, p.last_seen, f.first_seen::timestamp AS first_seen
+FROM page p
+LEFT JOIN LATERAL (
+    SELECT MIN(e.occurred_at) AS first_seen
+    FROM node_events e
+    WHERE 1=1
+    AND e.ip_addr = p.ip_addr
+    AND e.event = 'appeared'
+) f ON TRUE
+ORDER BY p.ip_addr;
+
+-- name: CountAggregatedNodes :one
+SELECT COUNT(DISTINCT n.ip_addr)
 FROM nodes n
 INNER JOIN sources s ON s.id = n.source_id
-WHERE 1=1 
+WHERE 1=1
 AND s.version < n.version 
-AND n.ip_addr > $1
-ORDER BY n.ip_addr
-LIMIT $2;
+AND (sqlc.narg(source_id)::int IS NULL OR n.source_id = sqlc.narg(source_id))
+AND (
+    sqlc.narg(list_id)::int IS NULL
+    OR sqlc.arg(invert)::boolean <> (n.ip_addr <<= ANY (
+        SELECT a.cidr
+        FROM allowlist_entry a 
+        WHERE 1=1 
+        AND a.list_id = sqlc.narg(list_id)
+    ))
+);
+
+-- name: BatchInsertNodes :batchexec
+INSERT INTO nodes (ip_addr, source_id, version) 
+VALUES ($1, $2, $3) 
+ON CONFLICT(ip_addr, source_id) 
+DO UPDATE 
+SET version = EXCLUDED.version, superseded_at = NULL, last_seen = now();
 
--- name: ListSourcesNodes :many
-SELECT n.ip_addr, n.source_id, n.version, s.last_execution
+-- name: ListSourceSnapshot :many
+SELECT n.ip_addr
 FROM nodes n
 INNER JOIN sources s ON s.id = n.source_id
-WHERE 1=1 
-AND s.version < n.version 
-AND s.id = $3
-AND n.ip_addr > $1
-ORDER BY n.ip_addr
-LIMIT $2;
+WHERE 1=1
+AND s.version < n.version
+AND s.id = $1;
 
--- name: ListNodesWithoutAllowlist :many
-SELECT n.ip_addr, n.source_id, n.version, s.last_execution
+-- name: MarkSupersededNodes :execrows
+-- Stamps nodes that are no longer part of their source's current snapshot 
+-- (including every node of a stopped source) so that the grace period can be
+-- measured from when they were first seen as superseded.
+UPDATE nodes n
+SET superseded_at = now()
+FROM sources s
+WHERE 1=1
+AND s.id = n.source_id
+AND n.version <= s.version
+AND n.superseded_at IS NULL;
+
+-- name: DeleteSupersededNodes :execrows
+-- The conditions are repeated on the delete target so that a row republished
+-- by a concurrent ingestion is checked again once its lock is released, the
+-- subquery only picks the batch.
+DELETE FROM nodes n
+USING sources s
+WHERE 1=1
+AND s.id = n.source_id
+AND n.version <= s.version
+AND n.superseded_at < now() - sqlc.arg(grace_period)::interval
+AND n.id IN (
+    SELECT sn.id
+    FROM nodes sn
+    INNER JOIN sources ss ON ss.id = sn.source_id
+    WHERE 1=1
+    AND sn.version <= ss.version
+    AND sn.superseded_at < now() - sqlc.arg(grace_period)::interval
+    LIMIT sqlc.arg(batch_size)
+);
+
+
+-- name: LookupNodes :many
+-- The optional allowlist behaves the same as the listing queries, only nodes
+-- inside the allowlist are returned unless `invert` is set, in which case only
+-- nodes outside of it are returned.
+SELECT n.ip_addr, n.source_id, n.version, s.last_execution, GREATEST(n.last_seen, s.last_confirmed_at)::timestamp AS last_seen, f.first_seen::timestamp AS first_seen
 FROM nodes n
 INNER JOIN sources s ON s.id = n.source_id
+LEFT JOIN LATERAL (
+    SELECT MIN(e.occurred_at) AS first_seen
+    FROM node_events e
+    WHERE 1=1
+    AND e.ip_addr = n.ip_addr
+    AND e.event = 'appeared'
+) f ON TRUE
 WHERE 1=1
 AND s.version < n.version 
-AND n.ip_addr > $1
-AND NOT n.ip_addr <<= ANY (
-    SELECT a.cidr
-    FROM allowlist_entry a 
-    WHERE 1=1 
-    AND a.list_id = $3
+AND n.ip_addr = ANY(sqlc.arg(ip_addrs)::inet[])
+AND (
+    sqlc.narg(list_id)::int IS NULL
+    OR sqlc.arg(invert)::boolean <> (n.ip_addr <<= ANY (
+        SELECT a.cidr
+        FROM allowlist_entry a 
+        WHERE 1=1 
+        AND a.list_id = sqlc.narg(list_id)
+    ))
 )
-ORDER BY n.ip_addr
-LIMIT $2;
+ORDER BY n.ip_addr, n.source_id;
 
--- name: ListFilteredAllowlistNodes :many
-SELECT n.ip_addr, n.source_id, n.version, s.last_execution
+-- name: ExportNodes :many
+-- Every aggregated node, see ExportNodesEach for reading it without buffering
+-- the result set.
+SELECT 
+    n.ip_addr,
+    array_agg(n.source_id ORDER BY n.source_id)::int[] AS source_ids,
+    MAX(GREATEST(n.last_seen, s.last_confirmed_at))::timestamp AS last_seen
 FROM nodes n
 INNER JOIN sources s ON s.id = n.source_id
 WHERE 1=1
 AND s.version < n.version 
-AND n.ip_addr > $1
-AND n.ip_addr <<= ANY (
-    SELECT a.cidr
-    FROM allowlist_entry a 
-    WHERE 1=1 
-    AND a.list_id = $3
+AND (
+    sqlc.narg(list_id)::int IS NULL
+    OR sqlc.arg(invert)::boolean <> (n.ip_addr <<= ANY (
+        SELECT a.cidr
+        FROM allowlist_entry a 
+        WHERE 1=1 
+        AND a.list_id = sqlc.narg(list_id)
+    ))
 )
-ORDER BY n.ip_addr
-LIMIT $2;
-
--- name: BatchInsertNodes :batchexec
-INSERT INTO nodes (ip_addr, source_id, version) 
-VALUES ($1, $2, $3) 
-ON CONFLICT(ip_addr, source_id) 
-DO UPDATE 
-SET version = EXCLUDED.version;
-
+GROUP BY n.ip_addr
+ORDER BY n.ip_addr;
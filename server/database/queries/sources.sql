@@ -1,8 +1,46 @@
 -- name: CreateSource :one
-INSERT INTO sources (name, url, period) 
-VALUES ($1, $2, $3) 
-ON CONFLICT(name) 
-DO NOTHING
+INSERT INTO sources (name, url, period, format, format_options, credentials) 
+VALUES ($1, $2, $3, $4, $5, $6) 
+RETURNING *;
+
+-- name: SetSourceCredentials :one
+-- Credentials are sealed with the source's id, so a new source stores them
+-- once its id is known.
+UPDATE sources
+SET credentials = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateSource :one
+-- Only changes the fields that are given, credentials are replaced (or
+-- cleared) when `replace_credentials` is set. When `feed_changed` is set the
+-- source is made due straight away, the validators, failures and pause left by
+-- the previous feed are forgotten and the generation moves on so that an ingestion
+-- of the previous feed that is still running can not publish. The published
+-- snapshot stays visible until the next ingestion replaces it.
+UPDATE sources
+SET 
+    name = COALESCE(sqlc.narg(name), name),
+    url = COALESCE(sqlc.narg(url), url),
+    period = COALESCE(sqlc.narg(period), period),
+    format = COALESCE(sqlc.narg(format), format),
+    format_options = COALESCE(sqlc.narg(format_options), format_options),
+    credentials = CASE WHEN sqlc.arg(replace_credentials)::boolean THEN sqlc.narg(credentials) ELSE credentials END,
+    last_execution = CASE WHEN sqlc.arg(feed_changed)::boolean THEN NULL ELSE last_execution END,
+    etag = CASE WHEN sqlc.arg(feed_changed)::boolean THEN NULL ELSE etag END,
+    last_modified = CASE WHEN sqlc.arg(feed_changed)::boolean THEN NULL ELSE last_modified END,
+    content_hash = CASE WHEN sqlc.arg(feed_changed)::boolean THEN NULL ELSE content_hash END,
+    consecutive_failures = CASE WHEN sqlc.arg(feed_changed)::boolean THEN 0 ELSE consecutive_failures END,
+    last_error = CASE WHEN sqlc.arg(feed_changed)::boolean THEN NULL ELSE last_error END,
+    paused_until = CASE WHEN sqlc.arg(feed_changed)::boolean THEN NULL ELSE paused_until END,
+    generation = CASE WHEN sqlc.arg(feed_changed)::boolean THEN generation + 1 ELSE generation END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteSource :one
+-- The source's nodes and their history are removed by the foreign keys.
+DELETE FROM sources
+WHERE id = $1
 RETURNING *;
 
 -- name: ListAllSources :many
@@ -13,12 +51,52 @@ AND id > $1
 ORDER BY id
 LIMIT $2;
 
--- name: ListEligableSources :many
-SELECT *
-FROM sources
+-- name: ClaimEligableSources :many
+-- Atomically leases up to `max_sources` eligable sources to a single ingester. 
+-- Rows locked by a concurrent claim are skipped so that two ingesters never 
+-- receive the same source, and a lease left behind by an ingester that died 
+-- becomes claimable again once it expires. Sources paused by the circuit
+-- breaker are skipped until their cooldown has elapsed, unless a run has been
+-- requested for them which also makes them due ahead of their period.
+UPDATE sources
+SET lease_owner = sqlc.arg(lease_owner), lease_expires_at = now() + sqlc.arg(lease_duration)::interval
+WHERE id IN (
+    SELECT c.id
+    FROM sources c
+    WHERE 1=1
+    AND c.running = TRUE
+    AND (c.lease_expires_at IS NULL OR c.lease_expires_at < now())
+    AND (
+        (
+            (c.last_execution IS NULL OR c.last_execution + c.period < now())
+            AND (c.paused_until IS NULL OR c.paused_until < now())
+        )
+        OR EXISTS (
+            SELECT 1 
+            FROM ingestion_runs r 
+            WHERE r.source_id = c.id 
+            AND r.status IN ('pending', 'running')
+        )
+    )
+    ORDER BY c.last_execution NULLS FIRST
+    LIMIT sqlc.arg(max_sources)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: RenewSourceLease :execrows
+UPDATE sources
+SET lease_expires_at = now() + sqlc.arg(lease_duration)::interval
 WHERE 1=1
-AND (last_execution IS NULL OR last_execution + period < now())
-AND running = TRUE;  
+AND id = sqlc.arg(id)
+AND lease_owner = sqlc.arg(lease_owner);
+
+-- name: ReleaseSourceLease :exec
+UPDATE sources
+SET lease_owner = NULL, lease_expires_at = NULL
+WHERE 1=1
+AND id = sqlc.arg(id)
+AND lease_owner = sqlc.arg(lease_owner);
 
 -- name: GetSource :one
 SELECT * 
@@ -29,18 +107,129 @@ LIMIT 1;
 
 -- name: PrepareExecution :one
 UPDATE sources
-SET last_execution = now(), version = version + 1
-WHERE id = $1
+SET last_execution = now()
+WHERE 1=1
+AND id = $1
+AND lease_owner = $2
+RETURNING *;
+
+-- name: PublishExecution :one
+-- Makes the nodes written with version + 2 visible and hides the previous
+-- snapshot. Fails with no rows if the source was stopped, published by
+-- someone else or given a new feed since `version` and `generation` were read.
+-- The validators of the feed that produced the snapshot are stored so the next
+-- fetch can be conditional.
+UPDATE sources
+SET 
+    version = version + 1,
+    last_confirmed_at = now(),
+    etag = sqlc.narg(etag),
+    last_modified = sqlc.narg(last_modified),
+    content_hash = sqlc.narg(content_hash)
+WHERE 1=1
+AND id = sqlc.arg(id)
+AND version = sqlc.arg(version)
+AND generation = sqlc.arg(generation)
+AND running = TRUE
 RETURNING *;
 
+-- name: RecordSourceUnchanged :execrows
+-- Keeps the published snapshot when the feed has not changed since it was
+-- fetched, only refreshing validators the server sent again and when the feed
+-- last confirmed the snapshot. Affects no rows if the source was stopped,
+-- published by someone else or given a new feed since `version` and
+-- `generation` were read.
+UPDATE sources
+SET 
+    etag = COALESCE(sqlc.narg(etag), etag),
+    last_modified = COALESCE(sqlc.narg(last_modified), last_modified),
+    last_confirmed_at = now()
+WHERE 1=1
+AND id = sqlc.arg(id)
+AND version = sqlc.arg(version)
+AND generation = sqlc.arg(generation)
+AND running = TRUE;
+
 -- name: StopSource :one
+-- Hides the published snapshot. The feed validators are cleared as well so the
+-- first run after the source is started again publishes a fresh snapshot.
+WITH disappeared AS (
+    INSERT INTO node_events (ip_addr, source_id, event)
+    SELECT n.ip_addr, n.source_id, 'disappeared'
+    FROM nodes n
+    INNER JOIN sources s ON s.id = n.source_id
+    WHERE 1=1
+    AND s.version < n.version
+    AND s.id = $1
+)
 UPDATE sources 
-SET running = FALSE, version = version + 1
-WHERE id = $1
+SET running = FALSE, version = version + 1, etag = NULL, last_modified = NULL, content_hash = NULL
+WHERE sources.id = $1
 RETURNING *;
 
 -- name: StartSource :one
+-- Also lifts a pause left by the circuit breaker. The failure count is kept so
+-- that the next failure pauses the source again straight away.
 UPDATE sources 
-SET running = TRUE
+SET running = TRUE, paused_until = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: RecordSourceSuccess :one
+UPDATE sources
+SET last_success = now(), last_error = NULL, consecutive_failures = 0, paused_until = NULL
 WHERE id = $1
 RETURNING *;
+
+-- name: RecordSourceFailure :one
+-- Pauses the source for `cooldown` once it has failed `failure_threshold`
+-- times in a row.
+UPDATE sources
+SET 
+    last_error = sqlc.arg(last_error), 
+    consecutive_failures = consecutive_failures + 1,
+    paused_until = CASE 
+        WHEN consecutive_failures + 1 >= sqlc.arg(failure_threshold)::int THEN now() + sqlc.arg(cooldown)::interval 
+        ELSE paused_until 
+    END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListSourceFreshness :many
+SELECT 
+    id,
+    name,
+    running,
+    last_execution,
+    last_success,
+    consecutive_failures,
+    lease_owner,
+    paused_until,
+    (COALESCE(running, FALSE) AND (last_success IS NULL OR last_success < now() - period * 2))::boolean AS stale
+FROM sources
+ORDER BY id;
+
+-- name: NextSourceDueIn :one
+-- Seconds until the next running source can be claimed, zero or less if one
+-- already can. Fails with no rows when there are no running sources.
+SELECT EXTRACT(EPOCH FROM (d.due_at - LOCALTIMESTAMP))::float8 AS due_in
+FROM (
+    SELECT 
+        CASE 
+            WHEN EXISTS (
+                SELECT 1 
+                FROM ingestion_runs r 
+                WHERE r.source_id = c.id 
+                AND r.status IN ('pending', 'running')
+            ) THEN COALESCE(c.lease_expires_at, LOCALTIMESTAMP)
+            ELSE GREATEST(
+                COALESCE(c.last_execution + c.period, LOCALTIMESTAMP),
+                COALESCE(c.paused_until, LOCALTIMESTAMP),
+                COALESCE(c.lease_expires_at, LOCALTIMESTAMP)
+            )
+        END AS due_at
+    FROM sources c
+    WHERE c.running = TRUE
+) d
+ORDER BY d.due_at
+LIMIT 1;
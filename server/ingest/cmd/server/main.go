@@ -2,118 +2,200 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
-	"net/netip"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
-	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jhamill34/prophet-security-takehome/server/config"
 	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/secrets"
+	"github.com/jhamill34/prophet-security-takehome/server/ingest/internal/compactor"
+	"github.com/jhamill34/prophet-security-takehome/server/ingest/internal/health"
+	"github.com/jhamill34/prophet-security-takehome/server/ingest/internal/ingester"
+	"github.com/jhamill34/prophet-security-takehome/server/ingest/internal/metrics"
 )
 
-func main() {
-	db := NewDatabase(context.TODO(), "host=localhost port=5432 user=prophet-th password=prophet-th dbname=prophet-th sslmode=disable")
-	queries := database.New(db)
+const healthShutdownTimeout = 5 * time.Second
 
-	httpClient := NewHttpClient()
-	ingester := NewIngester(queries, httpClient)
-	ingester.Run(context.TODO())
+func main() {
+	os.Exit(run())
 }
 
-func NewHttpClient() *http.Client {
-	return &http.Client{}
-}
+// run starts the ingester and returns the process exit code once it has shut
+// down, keeping deferred cleanup ahead of os.Exit.
+func run() int {
+	configPath := flag.String("config", os.Getenv(config.PathEnv), "Path to the yaml config file")
+	flag.Parse()
 
-type Ingester struct {
-	queries    *database.Queries
-	httpClient *http.Client
-}
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	slog.SetDefault(cfg.Log.Logger())
 
-func NewIngester(queries *database.Queries, httpClient *http.Client) *Ingester {
-	return &Ingester{
-		queries,
-		httpClient,
+	box, err := loadSecrets(cfg.Secrets)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
 	}
-}
 
-func (i *Ingester) Run(ctx context.Context) {
-	for {
-		sources, err := i.queries.ListEligableSources(ctx)
-		if err != nil {
-			panic(err)
-		}
+	instanceId := cfg.Ingest.InstanceId
+	if instanceId == "" {
+		instanceId = ingester.DefaultInstanceId()
+	}
 
-		for _, s := range sources {
-			childCtx := context.WithValue(ctx, "job_name", s.Name)
-			slog.InfoContext(ctx, "Bumping source version")
-			s, err := i.queries.PrepareExecution(ctx, s.ID)
-			if err != nil {
-				panic(err)
-			}
-			i.doIngestion(childCtx, s)
-		}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db := NewDatabase(ctx, cfg.Database)
+	defer db.Close()
+
+	gc := compactor.NewCompactor(database.New(db), compactor.Options{
+		Interval:     cfg.Ingest.Gc.Interval,
+		GracePeriod:  cfg.Ingest.Gc.GracePeriod,
+		BatchSize:    cfg.Ingest.Gc.BatchSize,
+		RunRetention: cfg.Ingest.Gc.RunRetention,
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		gc.Run(ctx)
+	}()
+
+	// A second signal while draining restores the default behaviour and
+	// kills the process straight away.
+	go func() {
+		<-ctx.Done()
+		slog.Info("Shutting down")
+		stop()
+	}()
+
+	httpClient := NewHttpClient()
+	ingest := ingester.NewIngester(db, httpClient, ingester.Options{
+		Concurrency:      cfg.Ingest.Concurrency,
+		SourceTimeout:    cfg.Ingest.SourceTimeout,
+		InstanceId:       instanceId,
+		LeaseDuration:    cfg.Ingest.LeaseDuration,
+		PollInterval:     cfg.Ingest.PollInterval,
+		ShutdownTimeout:  cfg.Ingest.ShutdownTimeout,
+		MaxAttempts:      cfg.Ingest.Retry.MaxAttempts,
+		InitialBackoff:   cfg.Ingest.Retry.InitialBackoff,
+		MaxBackoff:       cfg.Ingest.Retry.MaxBackoff,
+		FailureThreshold: cfg.Ingest.CircuitBreaker.FailureThreshold,
+		Cooldown:         cfg.Ingest.CircuitBreaker.Cooldown,
+		Secrets:          box,
+	})
+
+	queries := database.New(db)
+	metrics.RegisterAggregatedNodes(func(ctx context.Context) (int64, error) {
+		return queries.CountAggregatedNodes(ctx, database.CountAggregatedNodesParams{})
+	})
+
+	// The health listener outlives the run loop so that probes keep
+	// answering while in-flight ingestions drain.
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	defer stopHealth()
 
-		i.idle()
+	healthServer := &http.Server{
+		Addr:    cfg.Ingest.ListenAddress,
+		Handler: health.NewHandler(ingest, queries, cfg.Ingest.StallAfter),
 	}
-}
 
-func (i *Ingester) idle() {
-	slog.Info("Sleeping for 10 seconds")
-	time.Sleep(10 * time.Second)
-}
+	healthErrs := make(chan error, 1)
+	go func() {
+		slog.Info("Starting health server", slog.String("listen_address", cfg.Ingest.ListenAddress))
+		err := Serve(healthCtx, healthServer, healthShutdownTimeout)
+		if err != nil && healthCtx.Err() == nil {
+			// Without its health endpoints the ingester would look dead to
+			// an orchestrator, so a listener failure shuts everything down.
+			stop()
+		}
+		healthErrs <- err
+	}()
+
+	err = ingest.Run(ctx)
+	wg.Wait()
+	stopHealth()
 
-func (i *Ingester) doIngestion(ctx context.Context, source database.Source) {
-	slog.InfoContext(ctx, "Fetching canonical data")
-	req, err := http.NewRequestWithContext(ctx, "GET", source.Url, nil)
+	exitCode := 0
 	if err != nil {
-		panic(err)
+		slog.Error("Ingester did not shut down cleanly", slog.String("error", err.Error()))
+		exitCode = 1
 	}
 
-	resp, err := i.httpClient.Do(req)
+	err = <-healthErrs
 	if err != nil {
-		panic(err)
+		slog.Error("Health server stopped with an error", slog.String("error", err.Error()))
+		exitCode = 1
 	}
 
-	csvReader := csv.NewReader(resp.Body)
-	rows, err := csvReader.ReadAll()
-	if err != nil {
-		panic(err)
+	if exitCode != 0 {
+		return exitCode
 	}
 
-	var sourceId pgtype.Int4
-	sourceId.Scan(int64(source.ID))
+	slog.Info("Ingester stopped")
+	return 0
+}
 
-	insertNodes := make([]database.BatchInsertNodesParams, len(rows))
-	for i, row := range rows {
-		var newVersion pgtype.Int8
-		newVersion.Scan(source.Version.Int64 + 1)
-		addr, err := netip.ParseAddr(row[0])
-		if err != nil {
-			panic(err)
-		}
+// Serve runs the server until ctx is cancelled, then gives in-flight requests
+// up to timeout to finish.
+func Serve(ctx context.Context, server *http.Server, timeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
 
-		insertNodes[i] = database.BatchInsertNodesParams{
-			IpAddr:   addr,
-			SourceID: source.ID,
-			Version:  newVersion,
-		}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := server.Shutdown(shutdownCtx)
+	if err != nil {
+		return errors.Join(err, server.Close())
 	}
 
-	results := i.queries.BatchInsertNodes(ctx, insertNodes)
-	results.Exec(func(i int, err error) {
-		if err != nil {
-			slog.ErrorContext(ctx, err.Error())
-		}
+	return nil
+}
 
-		slog.InfoContext(ctx, fmt.Sprintf("Inserted %d nodes", i))
-	})
+// loadSecrets returns nil when no key is configured, sources with credentials
+// then fail to ingest.
+func loadSecrets(cfg config.SecretsConfig) (*secrets.Box, error) {
+	key, err := cfg.DecodeKey()
+	if err != nil || key == nil {
+		return nil, err
+	}
+
+	return secrets.NewBox(key)
 }
 
-func NewDatabase(ctx context.Context, connection string) *pgx.Conn {
-	db, err := pgx.Connect(ctx, connection)
+func NewHttpClient() *http.Client {
+	return &http.Client{}
+}
+
+func NewDatabase(ctx context.Context, cfg config.DatabaseConfig) *pgxpool.Pool {
+	poolConfig, err := cfg.PoolConfig()
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		panic(err)
 	}
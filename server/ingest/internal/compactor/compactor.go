@@ -0,0 +1,184 @@
+package compactor
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+	"github.com/jhamill34/prophet-security-takehome/server/ingest/internal/metrics"
+)
+
+const (
+	DefaultInterval     = 15 * time.Minute
+	DefaultGracePeriod  = 24 * time.Hour
+	DefaultBatchSize    = 5000
+	DefaultRunRetention = 7 * 24 * time.Hour
+)
+
+type Options struct {
+	// Interval is how often a compaction pass runs.
+	Interval time.Duration
+
+	// GracePeriod is how long a node row is kept after it was first seen
+	// outside of its source's current snapshot.
+	GracePeriod time.Duration
+
+	// BatchSize caps the number of rows removed by a single delete statement
+	// so that large backlogs don't hold locks for long.
+	BatchSize int
+
+	// RunRetention is how long finished ingestion runs are kept.
+	RunRetention time.Duration
+}
+
+// Stats are running totals since the compactor started.
+type Stats struct {
+	Passes    int64
+	Marked    int64
+	Reclaimed int64
+}
+
+// Compactor deletes node rows that have been superseded by a newer snapshot
+// of their source, or that belong to a stopped source, along with ingestion
+// runs that finished longer ago than the retention.
+type Compactor struct {
+	queries *database.Queries
+	opts    Options
+
+	passes    atomic.Int64
+	marked    atomic.Int64
+	reclaimed atomic.Int64
+}
+
+func NewCompactor(queries *database.Queries, opts Options) *Compactor {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	if opts.GracePeriod < 0 {
+		opts.GracePeriod = 0
+	}
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	if opts.RunRetention <= 0 {
+		opts.RunRetention = DefaultRunRetention
+	}
+
+	return &Compactor{
+		queries: queries,
+		opts:    opts,
+	}
+}
+
+func (c *Compactor) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		err := c.Compact(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Compaction failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Compact runs a single pass. Rows are first stamped as superseded and only
+// deleted once they have stayed superseded for the grace period.
+func (c *Compactor) Compact(ctx context.Context) error {
+	start := time.Now()
+
+	marked, err := c.queries.MarkSupersededNodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	gracePeriod := pgtype.Interval{
+		Microseconds: c.opts.GracePeriod.Microseconds(),
+		Valid:        true,
+	}
+
+	var reclaimed int64
+	for {
+		deleted, err := c.queries.DeleteSupersededNodes(ctx, database.DeleteSupersededNodesParams{
+			GracePeriod: gracePeriod,
+			BatchSize:   int32(c.opts.BatchSize),
+		})
+		reclaimed += deleted
+		if err != nil {
+			return err
+		}
+
+		if deleted < int64(c.opts.BatchSize) {
+			break
+		}
+	}
+
+	expiredRuns, err := c.deleteExpiredRuns(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.passes.Add(1)
+	c.marked.Add(marked)
+	c.reclaimed.Add(reclaimed)
+
+	metrics.GcPasses.Inc()
+	metrics.GcMarkedRows.Add(float64(marked))
+	metrics.GcReclaimedRows.Add(float64(reclaimed))
+
+	stats := c.Stats()
+	slog.InfoContext(
+		ctx,
+		"Compacted superseded nodes",
+		slog.Int64("marked", marked),
+		slog.Int64("reclaimed", reclaimed),
+		slog.Int64("total_reclaimed", stats.Reclaimed),
+		slog.Int64("expired_runs", expiredRuns),
+		slog.Duration("duration", time.Since(start)),
+	)
+
+	return nil
+}
+
+func (c *Compactor) deleteExpiredRuns(ctx context.Context) (int64, error) {
+	retention := pgtype.Interval{
+		Microseconds: c.opts.RunRetention.Microseconds(),
+		Valid:        true,
+	}
+
+	var expired int64
+	for {
+		deleted, err := c.queries.DeleteExpiredIngestionRuns(ctx, database.DeleteExpiredIngestionRunsParams{
+			Retention: retention,
+			BatchSize: int32(c.opts.BatchSize),
+		})
+		expired += deleted
+		if err != nil {
+			return expired, err
+		}
+
+		if deleted < int64(c.opts.BatchSize) {
+			return expired, nil
+		}
+	}
+}
+
+func (c *Compactor) Stats() Stats {
+	return Stats{
+		Passes:    c.passes.Load(),
+		Marked:    c.marked.Load(),
+		Reclaimed: c.reclaimed.Load(),
+	}
+}
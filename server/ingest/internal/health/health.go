@@ -0,0 +1,145 @@
+package health
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// LoopReporter is implemented by the ingester, it reports when its run loop
+// last made progress.
+type LoopReporter interface {
+	LastLoop() time.Time
+}
+
+type Handler struct {
+	loop       LoopReporter
+	queries    *database.Queries
+	stallAfter time.Duration
+}
+
+type loopStatus struct {
+	Healthy            bool    `json:"healthy"`
+	LastLoop           *string `json:"last_loop"`
+	LastLoopAgeSeconds float64 `json:"last_loop_age_seconds"`
+	StallAfterSeconds  float64 `json:"stall_after_seconds"`
+}
+
+type sourceFreshness struct {
+	Id                  int     `json:"id"`
+	Name                string  `json:"name"`
+	Running             bool    `json:"running"`
+	LastExecution       *string `json:"last_execution"`
+	LastSuccess         *string `json:"last_success"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	LeaseOwner          *string `json:"lease_owner"`
+	PausedUntil         *string `json:"paused_until"`
+	Stale               bool    `json:"stale"`
+}
+
+// NewHandler serves the ingester's health endpoints. The loop is considered
+// stalled once it has not started an iteration for stallAfter.
+func NewHandler(loop LoopReporter, queries *database.Queries, stallAfter time.Duration) http.Handler {
+	h := &Handler{
+		loop:       loop,
+		queries:    queries,
+		stallAfter: stallAfter,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", h.healthz)
+	mux.HandleFunc("GET /readyz", h.readyz)
+	mux.HandleFunc("GET /sources", h.sources)
+	mux.Handle("GET /metrics", promhttp.Handler())
+
+	return mux
+}
+
+// healthz fails once the run loop has stopped making progress.
+func (h *Handler) healthz(w http.ResponseWriter, r *http.Request) {
+	lastLoop := h.loop.LastLoop()
+
+	status := loopStatus{
+		StallAfterSeconds: h.stallAfter.Seconds(),
+	}
+
+	if !lastLoop.IsZero() {
+		age := time.Since(lastLoop)
+		status.LastLoop = optionalTime(lastLoop)
+		status.LastLoopAgeSeconds = age.Seconds()
+		status.Healthy = age < h.stallAfter
+	}
+
+	code := http.StatusOK
+	if !status.Healthy {
+		code = http.StatusServiceUnavailable
+	}
+
+	writeJson(w, code, status)
+}
+
+// readyz fails while the database can not be reached.
+func (h *Handler) readyz(w http.ResponseWriter, r *http.Request) {
+	_, _, err := h.queries.GetSchemaVersion(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Write([]byte("ok"))
+}
+
+// sources reports how recently every source was successfully ingested. A
+// running source is stale once it has gone two periods without a success.
+func (h *Handler) sources(w http.ResponseWriter, r *http.Request) {
+	rows, err := h.queries.ListSourceFreshness(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Unable to list source freshness", slog.String("error", err.Error()))
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]sourceFreshness, len(rows))
+	for i, row := range rows {
+		result[i] = sourceFreshness{
+			Id:                  int(row.ID),
+			Name:                row.Name,
+			Running:             row.Running.Bool,
+			LastExecution:       optionalTimestamp(row.LastExecution),
+			LastSuccess:         optionalTimestamp(row.LastSuccess),
+			ConsecutiveFailures: int(row.ConsecutiveFailures),
+			PausedUntil:         optionalTimestamp(row.PausedUntil),
+			Stale:               row.Stale,
+		}
+
+		if row.LeaseOwner.Valid {
+			result[i].LeaseOwner = &row.LeaseOwner.String
+		}
+	}
+
+	writeJson(w, http.StatusOK, result)
+}
+
+func writeJson(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
+
+func optionalTime(t time.Time) *string {
+	result := t.Format(time.RFC3339)
+	return &result
+}
+
+func optionalTimestamp(ts pgtype.Timestamp) *string {
+	if !ts.Valid {
+		return nil
+	}
+
+	return optionalTime(ts.Time)
+}
@@ -0,0 +1,71 @@
+package ingester
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+)
+
+var (
+	ErrSecretsKeyMissing = errors.New("Source has credentials but secrets.key is not configured")
+	ErrTooManyRedirects  = errors.New("Stopped after 10 redirects")
+)
+
+// applyCredentials opens the source's credentials and sets them on req. The
+// returned client must be used to send req, it keeps the custom headers from
+// following a redirect to another host the same way net/http already does for
+// Authorization.
+func (i *Ingester) applyCredentials(req *http.Request, source database.Source) (*http.Client, error) {
+	if len(source.Credentials) == 0 {
+		return i.httpClient, nil
+	}
+
+	if i.opts.Secrets == nil {
+		return nil, ErrSecretsKeyMissing
+	}
+
+	credentials, err := i.opts.Secrets.OpenCredentials(source.ID, source.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open credentials: %w", err)
+	}
+
+	for name, value := range credentials.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if credentials.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+credentials.BearerToken)
+	}
+
+	if credentials.BasicAuth != nil {
+		req.SetBasicAuth(credentials.BasicAuth.Username, credentials.BasicAuth.Password)
+	}
+
+	if len(credentials.Headers) == 0 {
+		return i.httpClient, nil
+	}
+
+	client := *i.httpClient
+	checkRedirect := client.CheckRedirect
+	client.CheckRedirect = func(next *http.Request, via []*http.Request) error {
+		if next.URL.Host != via[0].URL.Host {
+			for name := range credentials.Headers {
+				next.Header.Del(name)
+			}
+		}
+
+		if checkRedirect != nil {
+			return checkRedirect(next, via)
+		}
+
+		if len(via) >= 10 {
+			return ErrTooManyRedirects
+		}
+
+		return nil
+	}
+
+	return &client, nil
+}
@@ -0,0 +1,921 @@
+package ingester
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/netip"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/secrets"
+	"github.com/jhamill34/prophet-security-takehome/server/ingest/internal/metrics"
+	"github.com/jhamill34/prophet-security-takehome/server/ingest/internal/parser"
+)
+
+var (
+	ErrUnexpectedStatus = errors.New("Unexpected response status")
+	ErrSourceChanged    = errors.New("Source was stopped or updated during ingestion")
+	ErrShutdownTimeout  = errors.New("In-flight ingestions did not finish before the shutdown timeout")
+	ErrNoValidEntries   = errors.New("Every entry in the feed was rejected")
+)
+
+const (
+	DefaultLeaseDuration   = 10 * time.Minute
+	DefaultPollInterval    = 10 * time.Second
+	DefaultShutdownTimeout = time.Minute
+	DefaultInitialBackoff  = time.Second
+	DefaultMaxBackoff      = 30 * time.Second
+	DefaultCooldown        = time.Hour
+
+	// minIdle keeps the run loop from spinning when a source is due but could
+	// not be claimed, maxIdle bounds the sleep in case a wake up was missed.
+	minIdle = time.Second
+	maxIdle = time.Hour
+
+	EventAppeared    = "appeared"
+	EventDisappeared = "disappeared"
+
+	RunStatusSuccess   = "success"
+	RunStatusUnchanged = "unchanged"
+	RunStatusFailure   = "failure"
+)
+
+type Options struct {
+	// Concurrency is the number of sources that can be ingested at the same time.
+	Concurrency int
+
+	// SourceTimeout bounds a single ingestion, including fetching the feed and
+	// writing its nodes.
+	SourceTimeout time.Duration
+
+	// InstanceId identifies this ingester as the owner of the sources it leases.
+	// It must be unique across every ingester sharing the database.
+	InstanceId string
+
+	// LeaseDuration is how long a claimed source stays reserved for this
+	// ingester without being renewed. If the ingester dies mid ingestion the
+	// source becomes claimable by another instance once the lease expires.
+	LeaseDuration time.Duration
+
+	// PollInterval is how long the ingester sleeps between looking for
+	// sources that are due while it can not receive notifications. Once it
+	// listens it sleeps until the next source is due instead.
+	PollInterval time.Duration
+
+	// ShutdownTimeout is how long in-flight ingestions may keep running once
+	// Run's context is cancelled. Ingestions still running after it are
+	// cancelled and their write transactions rolled back.
+	ShutdownTimeout time.Duration
+
+	// MaxAttempts is the number of times a fetch is tried within a single
+	// ingestion when it fails with a network error or a 5xx or 429 response.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry, it doubles with
+	// every further retry up to MaxBackoff and is jittered to spread retries
+	// against the same server apart.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// FailureThreshold is the number of consecutive failed ingestions after
+	// which a source is paused for Cooldown. Starting the source lifts the
+	// pause early.
+	FailureThreshold int
+	Cooldown         time.Duration
+
+	// Secrets opens the credentials sent with a source's requests, sources
+	// with credentials fail to ingest while it is nil.
+	Secrets *secrets.Box
+}
+
+type Ingester struct {
+	db         *pgxpool.Pool
+	queries    *database.Queries
+	httpClient *http.Client
+	opts       Options
+	owner      pgtype.Text
+	lease      pgtype.Interval
+	cooldown   pgtype.Interval
+
+	mu       sync.Mutex
+	inFlight map[int32]struct{}
+
+	// lastLoop holds the unix nano time the Run loop last started an
+	// iteration or checked in while idle, it stops moving forward if the
+	// loop stalls.
+	lastLoop atomic.Int64
+
+	// listening is set while notifications on database.SourcesChannel are
+	// being received, wake is signalled by them and by finished ingestions.
+	listening atomic.Bool
+	wake      chan struct{}
+}
+
+func NewIngester(db *pgxpool.Pool, httpClient *http.Client, opts Options) *Ingester {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+
+	if opts.LeaseDuration <= 0 {
+		opts.LeaseDuration = DefaultLeaseDuration
+	}
+
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+
+	if opts.ShutdownTimeout <= 0 {
+		opts.ShutdownTimeout = DefaultShutdownTimeout
+	}
+
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultInitialBackoff
+	}
+
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = max(DefaultMaxBackoff, opts.InitialBackoff)
+	}
+
+	if opts.FailureThreshold < 1 {
+		opts.FailureThreshold = 1
+	}
+
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = DefaultCooldown
+	}
+
+	var owner pgtype.Text
+	owner.Scan(opts.InstanceId)
+
+	return &Ingester{
+		db:         db,
+		queries:    database.New(db),
+		httpClient: httpClient,
+		opts:       opts,
+		owner:      owner,
+		lease: pgtype.Interval{
+			Microseconds: opts.LeaseDuration.Microseconds(),
+			Valid:        true,
+		},
+		cooldown: pgtype.Interval{
+			Microseconds: opts.Cooldown.Microseconds(),
+			Valid:        true,
+		},
+		inFlight: make(map[int32]struct{}),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// DefaultInstanceId derives an owner id from the host name and process id.
+func DefaultInstanceId() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// Run claims and ingests sources until ctx is cancelled. It then stops
+// claiming new sources and waits for in-flight ingestions to drain, returning
+// ErrShutdownTimeout if some of them had to be cancelled.
+func (i *Ingester) Run(ctx context.Context) error {
+	workers := make(chan struct{}, i.opts.Concurrency)
+
+	// Ingestions run on their own context so that a shutdown lets them finish
+	// instead of aborting them half way through.
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		i.listen(ctx)
+	}()
+
+	for ctx.Err() == nil {
+		i.lastLoop.Store(time.Now().UnixNano())
+
+		// Only lease as many sources as there are idle workers so that
+		// claimed sources never sit in a queue while their lease runs down.
+		available := cap(workers) - len(workers)
+		if available > 0 {
+			sources, err := i.queries.ClaimEligableSources(ctx, database.ClaimEligableSourcesParams{
+				LeaseOwner:    i.owner,
+				LeaseDuration: i.lease,
+				MaxSources:    int32(available),
+			})
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "Unable to claim eligable sources", slog.String("error", err.Error()))
+			}
+
+			for _, s := range sources {
+				if !i.claim(s.ID) {
+					slog.DebugContext(ctx, "Source is already being ingested", slog.String("source", s.Name))
+					continue
+				}
+
+				workers <- struct{}{}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer i.signal()
+					defer func() { <-workers }()
+					defer i.release(workCtx, s.ID)
+
+					i.ingestSource(workCtx, s)
+				}()
+			}
+		}
+
+		i.idle(ctx, len(workers) == cap(workers))
+	}
+
+	return i.drain(&wg, cancelWork)
+}
+
+// LastLoop returns when the Run loop last started an iteration or checked in
+// while idle, or the zero time if it has not started yet.
+func (i *Ingester) LastLoop() time.Time {
+	nanos := i.lastLoop.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, nanos)
+}
+
+// drain waits for in-flight ingestions, cancelling them once the shutdown
+// timeout has passed.
+func (i *Ingester) drain(wg *sync.WaitGroup, cancelWork context.CancelFunc) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	slog.Info("Waiting for in-flight ingestions to finish", slog.Duration("shutdown_timeout", i.opts.ShutdownTimeout))
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(i.opts.ShutdownTimeout):
+	}
+
+	slog.Warn("Cancelling in-flight ingestions")
+	cancelWork()
+	<-done
+
+	return ErrShutdownTimeout
+}
+
+// claim marks the source as in flight, returning false if a worker is
+// already ingesting it. Leases already keep other instances away, this
+// guards against a lease that expired while this instance was still working.
+func (i *Ingester) claim(id int32) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, ok := i.inFlight[id]; ok {
+		return false
+	}
+
+	i.inFlight[id] = struct{}{}
+	return true
+}
+
+func (i *Ingester) release(ctx context.Context, id int32) {
+	// The lease is released even when the ingestion was cancelled so that
+	// another ingester does not have to wait for it to expire.
+	ctx = context.WithoutCancel(ctx)
+
+	err := i.queries.ReleaseSourceLease(ctx, database.ReleaseSourceLeaseParams{
+		ID:         id,
+		LeaseOwner: i.owner,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Unable to release source lease", slog.Int("source_id", int(id)), slog.String("error", err.Error()))
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.inFlight, id)
+}
+
+// renewLease keeps the lease on the source alive until ctx is done.
+func (i *Ingester) renewLease(ctx context.Context, logger *slog.Logger, id int32) {
+	ticker := time.NewTicker(i.opts.LeaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewed, err := i.queries.RenewSourceLease(ctx, database.RenewSourceLeaseParams{
+				ID:            id,
+				LeaseOwner:    i.owner,
+				LeaseDuration: i.lease,
+			})
+			if err != nil {
+				logger.ErrorContext(ctx, "Unable to renew source lease", slog.String("error", err.Error()))
+			} else if renewed == 0 {
+				logger.WarnContext(ctx, "Source lease was lost to another ingester")
+			}
+		}
+	}
+}
+
+// idle sleeps until the next source is due, waking early when a source
+// changes, an ingestion finishes or ctx is cancelled. Without notifications,
+// or while every worker is busy, it sleeps for the poll interval instead.
+func (i *Ingester) idle(ctx context.Context, busy bool) {
+	delay := i.opts.PollInterval
+	if i.listening.Load() && !busy {
+		delay = i.nextDue(ctx)
+	}
+
+	slog.Debug("Sleeping", slog.Duration("delay", delay), slog.Bool("listening", i.listening.Load()))
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	// Long sleeps check in every poll interval so that the health check can
+	// still tell a sleeping loop from a stalled one.
+	heartbeat := time.NewTicker(i.opts.PollInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-i.wake:
+			return
+		case <-timer.C:
+			return
+		case <-heartbeat.C:
+			i.lastLoop.Store(time.Now().UnixNano())
+		}
+	}
+}
+
+// nextDue returns how long to sleep until the next running source can be
+// claimed, bounded by minIdle and maxIdle.
+func (i *Ingester) nextDue(ctx context.Context) time.Duration {
+	dueIn, err := i.queries.NextSourceDueIn(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return maxIdle
+	}
+
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.ErrorContext(ctx, "Unable to find the next due source", slog.String("error", err.Error()))
+		}
+		return i.opts.PollInterval
+	}
+
+	if dueIn >= maxIdle.Seconds() {
+		return maxIdle
+	}
+
+	return max(time.Duration(dueIn*float64(time.Second)), minIdle)
+}
+
+// signal wakes the Run loop if it is idle, or makes its next idle return
+// straight away.
+func (i *Ingester) signal() {
+	select {
+	case i.wake <- struct{}{}:
+	default:
+	}
+}
+
+// ingestSource runs a single ingestion for the source and records the outcome
+// on the source row. Errors never escape so that one broken feed cannot stop
+// the remaining sources from being processed.
+func (i *Ingester) ingestSource(ctx context.Context, s database.Source) {
+	logger := slog.With(slog.String("source", s.Name), slog.Int("source_id", int(s.ID)))
+
+	leaseCtx, stopRenewing := context.WithCancel(ctx)
+	defer stopRenewing()
+	go i.renewLease(leaseCtx, logger, s.ID)
+
+	s, err := i.queries.PrepareExecution(ctx, database.PrepareExecutionParams{
+		ID:         s.ID,
+		LeaseOwner: i.owner,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.WarnContext(ctx, "Source lease was lost before ingestion started")
+		return
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to prepare execution", slog.String("error", err.Error()))
+		return
+	}
+
+	// Runs requested through the api are answered by this ingestion, without
+	// any it records a scheduled run of its own. If it is cancelled the runs
+	// stay running and are taken over by the next ingestion.
+	runIds, err := i.queries.StartRequestedIngestionRuns(ctx, s.ID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to start requested runs", slog.String("error", err.Error()))
+	}
+
+	if err == nil && len(runIds) == 0 {
+		runId, err := i.queries.StartScheduledIngestionRun(ctx, s.ID)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to start scheduled run", slog.String("error", err.Error()))
+		} else {
+			runIds = append(runIds, runId)
+		}
+	}
+
+	childCtx := ctx
+	if i.opts.SourceTimeout > 0 {
+		var cancel context.CancelFunc
+		childCtx, cancel = context.WithTimeout(ctx, i.opts.SourceTimeout)
+		defer cancel()
+	}
+
+	var stats runStats
+	published, err := i.doIngestion(childCtx, logger, s, &stats)
+	if err != nil && ctx.Err() != nil {
+		// Cancelled by a shutdown, the write transaction has been rolled back
+		// and the source is not to blame so no failure is recorded.
+		logger.WarnContext(ctx, "Ingestion cancelled by shutdown", slog.String("error", err.Error()))
+		return
+	}
+
+	if errors.Is(err, ErrSourceChanged) {
+		// Stopped or changed through the api (or published by someone else)
+		// while it was being ingested, the feed is not to blame so the runs
+		// are closed without recording a source failure.
+		logger.WarnContext(ctx, "Ingestion discarded", slog.String("error", err.Error()))
+		metrics.Runs.WithLabelValues(s.Name, RunStatusFailure).Inc()
+
+		var runError pgtype.Text
+		runError.Scan(err.Error())
+		i.finishRuns(ctx, logger, runIds, RunStatusFailure, runError, s.Version.Int64, stats)
+		return
+	}
+
+	if err != nil {
+		logger.ErrorContext(ctx, "Ingestion failed", slog.String("error", err.Error()))
+		metrics.Runs.WithLabelValues(s.Name, RunStatusFailure).Inc()
+
+		var lastError pgtype.Text
+		lastError.Scan(err.Error())
+		i.finishRuns(ctx, logger, runIds, RunStatusFailure, lastError, s.Version.Int64, stats)
+
+		failed, err := i.queries.RecordSourceFailure(ctx, database.RecordSourceFailureParams{
+			ID:               s.ID,
+			LastError:        lastError,
+			FailureThreshold: int32(i.opts.FailureThreshold),
+			Cooldown:         i.cooldown,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to record source failure", slog.String("error", err.Error()))
+			return
+		}
+
+		if int(failed.ConsecutiveFailures) >= i.opts.FailureThreshold {
+			metrics.SourcePauses.WithLabelValues(s.Name).Inc()
+			logger.WarnContext(ctx, "Source paused after repeated failures",
+				slog.Int("consecutive_failures", int(failed.ConsecutiveFailures)),
+				slog.Time("paused_until", failed.PausedUntil.Time),
+			)
+		}
+		return
+	}
+
+	if published {
+		metrics.Runs.WithLabelValues(s.Name, RunStatusSuccess).Inc()
+		i.finishRuns(ctx, logger, runIds, RunStatusSuccess, pgtype.Text{}, s.Version.Int64+1, stats)
+	} else {
+		metrics.Runs.WithLabelValues(s.Name, RunStatusUnchanged).Inc()
+		i.finishRuns(ctx, logger, runIds, RunStatusUnchanged, pgtype.Text{}, s.Version.Int64, stats)
+	}
+
+	_, err = i.queries.RecordSourceSuccess(ctx, s.ID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to record source success", slog.String("error", err.Error()))
+	}
+}
+
+// transientError marks fetch failures that are worth retrying, network errors
+// and 5xx or 429 responses.
+type transientError struct {
+	err error
+}
+
+func (e transientError) Error() string {
+	return e.err.Error()
+}
+
+func (e transientError) Unwrap() error {
+	return e.err
+}
+
+// checkStatus fails any non 2xx response, marking 5xx and 429 as transient so
+// they are retried.
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return transientError{err: fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	return nil
+}
+
+// feed is a fetched and parsed source feed along with the validators used to
+// make the next fetch conditional.
+type feed struct {
+	parsed       parser.Result
+	etag         pgtype.Text
+	lastModified pgtype.Text
+	contentHash  []byte
+
+	// unchanged is set when the server answered 304 Not Modified or the body
+	// hashed the same as the published snapshot, parsed is empty if it was a 304.
+	unchanged bool
+}
+
+// runStats is what an ingestion saw of its feed, recorded on its runs.
+type runStats struct {
+	// httpStatus is the status of the last fetch attempt, unset if it never
+	// got a response.
+	httpStatus   pgtype.Int4
+	bytesFetched int64
+	rowsParsed   int
+	rowsRejected int
+	nodesAdded   int
+	nodesRemoved int
+}
+
+// finishRuns records the outcome of an ingestion on the runs it answered,
+// version is the source version visible once it finished.
+func (i *Ingester) finishRuns(ctx context.Context, logger *slog.Logger, ids []int64, status string, runError pgtype.Text, version int64, stats runStats) {
+	if len(ids) == 0 {
+		return
+	}
+
+	err := i.queries.FinishIngestionRuns(ctx, database.FinishIngestionRunsParams{
+		Ids:          ids,
+		Status:       status,
+		Error:        runError,
+		Version:      version,
+		HttpStatus:   stats.httpStatus,
+		BytesFetched: stats.bytesFetched,
+		RowsParsed:   int32(stats.rowsParsed),
+		RowsRejected: int32(stats.rowsRejected),
+		NodesAdded:   int32(stats.nodesAdded),
+		NodesRemoved: int32(stats.nodesRemoved),
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to record the outcome of the ingestion runs", slog.String("error", err.Error()))
+	}
+}
+
+// doIngestion fetches and parses the feed before touching the nodes table.
+// The new snapshot is then written and published in a single transaction so
+// readers either see the previous snapshot or the complete new one. A feed
+// that has not changed since the last published snapshot leaves the nodes
+// table alone, in which case published is false.
+func (i *Ingester) doIngestion(ctx context.Context, logger *slog.Logger, source database.Source, stats *runStats) (published bool, err error) {
+	fetched, err := i.fetchWithRetries(ctx, logger, source, stats)
+	if err != nil {
+		return false, err
+	}
+
+	if fetched.unchanged {
+		return false, i.keepSnapshot(ctx, logger, source, fetched)
+	}
+
+	return true, i.publish(ctx, logger, source, fetched, stats)
+}
+
+// fetchWithRetries retries transient fetch failures with a jittered
+// exponential backoff, giving up after MaxAttempts or once ctx is done.
+func (i *Ingester) fetchWithRetries(ctx context.Context, logger *slog.Logger, source database.Source, stats *runStats) (feed, error) {
+	for attempt := 1; ; attempt++ {
+		fetched, err := i.fetch(ctx, logger, source, stats)
+		if err == nil || !errors.As(err, &transientError{}) || attempt >= i.opts.MaxAttempts {
+			return fetched, err
+		}
+
+		delay := i.backoff(attempt)
+		logger.WarnContext(ctx, "Fetch failed, retrying",
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.String("error", err.Error()),
+		)
+		metrics.FetchRetries.WithLabelValues(source.Name).Inc()
+
+		select {
+		case <-ctx.Done():
+			return feed{}, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// backoff returns the delay before retrying after the given attempt, a random
+// value between half and all of InitialBackoff * 2^(attempt-1) capped at MaxBackoff.
+func (i *Ingester) backoff(attempt int) time.Duration {
+	delay := i.opts.InitialBackoff
+	for n := 1; n < attempt && delay < i.opts.MaxBackoff; n++ {
+		delay *= 2
+	}
+	delay = min(delay, i.opts.MaxBackoff)
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+func (i *Ingester) fetch(ctx context.Context, logger *slog.Logger, source database.Source, stats *runStats) (feed, error) {
+	opts, err := parser.ParseOptions(source.FormatOptions)
+	if err != nil {
+		return feed{}, fmt.Errorf("invalid format options: %w", err)
+	}
+
+	feedParser, err := parser.New(source.Format, opts)
+	if err != nil {
+		return feed{}, err
+	}
+
+	logger.InfoContext(ctx, "Fetching canonical data")
+	req, err := http.NewRequestWithContext(ctx, "GET", source.Url, nil)
+	if err != nil {
+		return feed{}, err
+	}
+
+	if source.Etag.Valid {
+		req.Header.Set("If-None-Match", source.Etag.String)
+	}
+
+	if source.LastModified.Valid {
+		req.Header.Set("If-Modified-Since", source.LastModified.String)
+	}
+
+	httpClient, err := i.applyCredentials(req, source)
+	if err != nil {
+		return feed{}, err
+	}
+
+	start := time.Now()
+	defer func() {
+		metrics.FetchDuration.WithLabelValues(source.Name).Observe(time.Since(start).Seconds())
+	}()
+
+	stats.httpStatus = pgtype.Int4{}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		metrics.FetchResponses.WithLabelValues(source.Name, "error").Inc()
+		return feed{}, transientError{err: err}
+	}
+	defer resp.Body.Close()
+
+	metrics.FetchResponses.WithLabelValues(source.Name, strconv.Itoa(resp.StatusCode)).Inc()
+	stats.httpStatus = pgtype.Int4{Int32: int32(resp.StatusCode), Valid: true}
+
+	result := feed{
+		etag:         optionalHeader(resp.Header, "ETag"),
+		lastModified: optionalHeader(resp.Header, "Last-Modified"),
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		result.unchanged = true
+		return result, nil
+	}
+
+	if err := checkStatus(resp); err != nil {
+		return feed{}, err
+	}
+
+	body := &countingReader{reader: resp.Body}
+	defer func() {
+		metrics.FetchedBytes.WithLabelValues(source.Name).Add(float64(body.count))
+		stats.bytesFetched += body.count
+	}()
+
+	hash := sha256.New()
+	parsed, err := feedParser.Parse(io.TeeReader(body, hash))
+	if err != nil {
+		return feed{}, fmt.Errorf("unable to parse feed: %w", err)
+	}
+
+	// Drain whatever the parser left unread so the hash covers the whole body.
+	_, err = io.Copy(hash, body)
+	if err != nil {
+		return feed{}, fmt.Errorf("unable to read feed: %w", err)
+	}
+
+	result.parsed = parsed
+	result.contentHash = hash.Sum(nil)
+	result.unchanged = bytes.Equal(result.contentHash, source.ContentHash)
+
+	metrics.ParsedRows.WithLabelValues(source.Name).Add(float64(len(parsed.Addrs)))
+	metrics.InvalidRows.WithLabelValues(source.Name).Add(float64(parsed.Rejected))
+	stats.rowsParsed = len(parsed.Addrs)
+	stats.rowsRejected = parsed.Rejected
+
+	// An error page or a feed whose layout no longer matches its format
+	// would otherwise publish an empty snapshot and hide every node.
+	if len(parsed.Addrs) == 0 && parsed.Rejected > 0 {
+		return feed{}, fmt.Errorf("%w: %d rejected", ErrNoValidEntries, parsed.Rejected)
+	}
+
+	if parsed.Rejected > 0 {
+		logger.WarnContext(ctx, "Skipped invalid feed entries", slog.Int("rejected", parsed.Rejected))
+	}
+
+	return result, nil
+}
+
+func optionalHeader(header http.Header, name string) pgtype.Text {
+	value := header.Get(name)
+	return pgtype.Text{String: value, Valid: value != ""}
+}
+
+// keepSnapshot leaves the published snapshot in place, last_execution has
+// already been refreshed by PrepareExecution.
+func (i *Ingester) keepSnapshot(ctx context.Context, logger *slog.Logger, source database.Source, fetched feed) error {
+	updated, err := i.queries.RecordSourceUnchanged(ctx, database.RecordSourceUnchangedParams{
+		ID:           source.ID,
+		Version:      source.Version,
+		Generation:   source.Generation,
+		Etag:         fetched.etag,
+		LastModified: fetched.lastModified,
+	})
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return ErrSourceChanged
+	}
+
+	logger.InfoContext(ctx, "Feed has not changed since the last published snapshot")
+	return nil
+}
+
+// publish writes the nodes under the pending version (source.version + 2) and
+// flips the source to source.version + 1, which makes them the visible
+// snapshot. Nothing is visible to readers until the transaction commits.
+func (i *Ingester) publish(ctx context.Context, logger *slog.Logger, source database.Source, fetched feed, stats *runStats) error {
+	tx, err := i.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := i.queries.WithTx(tx)
+
+	previous, err := queries.ListSourceSnapshot(ctx, source.ID)
+	if err != nil {
+		return err
+	}
+
+	var pendingVersion pgtype.Int8
+	pendingVersion.Scan(source.Version.Int64 + 2)
+
+	insertNodes := make([]database.BatchInsertNodesParams, len(fetched.parsed.Addrs))
+	for i, addr := range fetched.parsed.Addrs {
+		insertNodes[i] = database.BatchInsertNodesParams{
+			IpAddr:   addr,
+			SourceID: source.ID,
+			Version:  pendingVersion,
+		}
+	}
+
+	var insertErr error
+	results := queries.BatchInsertNodes(ctx, insertNodes)
+	results.Exec(func(i int, err error) {
+		if err != nil && insertErr == nil {
+			insertErr = err
+		}
+	})
+	if insertErr != nil {
+		return fmt.Errorf("unable to insert nodes: %w", insertErr)
+	}
+
+	events := diffSnapshots(source.ID, previous, fetched.parsed.Addrs)
+	if len(events) > 0 {
+		_, err = queries.InsertNodeEvents(ctx, events)
+		if err != nil {
+			return fmt.Errorf("unable to record node history: %w", err)
+		}
+	}
+
+	_, err = queries.PublishExecution(ctx, database.PublishExecutionParams{
+		ID:           source.ID,
+		Version:      source.Version,
+		Generation:   source.Generation,
+		Etag:         fetched.etag,
+		LastModified: fetched.lastModified,
+		ContentHash:  fetched.contentHash,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrSourceChanged
+	}
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, fmt.Sprintf("Published %d nodes", len(insertNodes)), slog.Int("history_events", len(events)))
+
+	for _, event := range events {
+		if event.Event == EventAppeared {
+			stats.nodesAdded++
+		} else {
+			stats.nodesRemoved++
+		}
+	}
+
+	metrics.NodesAdded.WithLabelValues(source.Name).Add(float64(stats.nodesAdded))
+	metrics.NodesRemoved.WithLabelValues(source.Name).Add(float64(stats.nodesRemoved))
+
+	return nil
+}
+
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+// diffSnapshots produces an appeared event for every address that is new to
+// the source and a disappeared event for every address it no longer reports,
+// each address at most once.
+func diffSnapshots(sourceId int32, previous []netip.Addr, current []netip.Addr) []database.InsertNodeEventsParams {
+	previousSet := make(map[netip.Addr]struct{}, len(previous))
+	for _, addr := range previous {
+		previousSet[addr] = struct{}{}
+	}
+
+	currentSet := make(map[netip.Addr]struct{}, len(current))
+	events := make([]database.InsertNodeEventsParams, 0)
+	for _, addr := range current {
+		if _, ok := currentSet[addr]; ok {
+			continue
+		}
+		currentSet[addr] = struct{}{}
+
+		if _, ok := previousSet[addr]; !ok {
+			events = append(events, database.InsertNodeEventsParams{
+				IpAddr:   addr,
+				SourceID: sourceId,
+				Event:    EventAppeared,
+			})
+		}
+	}
+
+	for _, addr := range previous {
+		if _, ok := currentSet[addr]; ok {
+			continue
+		}
+		currentSet[addr] = struct{}{}
+
+		events = append(events, database.InsertNodeEventsParams{
+			IpAddr:   addr,
+			SourceID: sourceId,
+			Event:    EventDisappeared,
+		})
+	}
+
+	return events
+}
@@ -0,0 +1,175 @@
+package ingester
+
+import (
+	"errors"
+	"net/http"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	a := netip.MustParseAddr("1.1.1.1")
+	b := netip.MustParseAddr("2.2.2.2")
+	c := netip.MustParseAddr("2001:db8::1")
+
+	appeared := func(addr netip.Addr) database.InsertNodeEventsParams {
+		return database.InsertNodeEventsParams{IpAddr: addr, SourceID: 7, Event: EventAppeared}
+	}
+
+	disappeared := func(addr netip.Addr) database.InsertNodeEventsParams {
+		return database.InsertNodeEventsParams{IpAddr: addr, SourceID: 7, Event: EventDisappeared}
+	}
+
+	tests := []struct {
+		name     string
+		previous []netip.Addr
+		current  []netip.Addr
+		want     []database.InsertNodeEventsParams
+	}{
+		{
+			name:     "both empty",
+			previous: nil,
+			current:  nil,
+			want:     []database.InsertNodeEventsParams{},
+		},
+		{
+			name:     "empty previous snapshot",
+			previous: nil,
+			current:  []netip.Addr{a, b},
+			want:     []database.InsertNodeEventsParams{appeared(a), appeared(b)},
+		},
+		{
+			name:     "empty new snapshot",
+			previous: []netip.Addr{a, b},
+			current:  nil,
+			want:     []database.InsertNodeEventsParams{disappeared(a), disappeared(b)},
+		},
+		{
+			name:     "full overlap",
+			previous: []netip.Addr{a, b},
+			current:  []netip.Addr{b, a},
+			want:     []database.InsertNodeEventsParams{},
+		},
+		{
+			name:     "partial overlap",
+			previous: []netip.Addr{a, b},
+			current:  []netip.Addr{b, c},
+			want:     []database.InsertNodeEventsParams{appeared(c), disappeared(a)},
+		},
+		{
+			name:     "duplicate new addresses",
+			previous: []netip.Addr{b},
+			current:  []netip.Addr{a, a, b, b},
+			want:     []database.InsertNodeEventsParams{appeared(a)},
+		},
+		{
+			name:     "duplicate previous addresses",
+			previous: []netip.Addr{a, a, b},
+			current:  []netip.Addr{b},
+			want:     []database.InsertNodeEventsParams{disappeared(a)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffSnapshots(7, tt.previous, tt.current)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffSnapshots() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	i := &Ingester{opts: Options{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}}
+
+	tests := []struct {
+		attempt int
+		delay   time.Duration
+	}{
+		{attempt: 1, delay: 100 * time.Millisecond},
+		{attempt: 2, delay: 200 * time.Millisecond},
+		{attempt: 3, delay: 400 * time.Millisecond},
+		{attempt: 4, delay: 800 * time.Millisecond},
+		{attempt: 5, delay: time.Second},
+		{attempt: 50, delay: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.attempt), func(t *testing.T) {
+			for range 100 {
+				got := i.backoff(tt.attempt)
+				if got < tt.delay/2 || got > tt.delay {
+					t.Fatalf("backoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.delay/2, tt.delay)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffInitialAboveMax(t *testing.T) {
+	i := &Ingester{opts: Options{
+		InitialBackoff: 5 * time.Second,
+		MaxBackoff:     time.Second,
+	}}
+
+	for range 100 {
+		if got := i.backoff(1); got > time.Second {
+			t.Fatalf("backoff(1) = %v, want at most %v", got, time.Second)
+		}
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	tests := []struct {
+		status    int
+		failed    bool
+		transient bool
+	}{
+		{status: http.StatusOK},
+		{status: http.StatusNoContent},
+		{status: http.StatusMultipleChoices, failed: true},
+		{status: http.StatusBadRequest, failed: true},
+		{status: http.StatusUnauthorized, failed: true},
+		{status: http.StatusForbidden, failed: true},
+		{status: http.StatusNotFound, failed: true},
+		{status: http.StatusTooManyRequests, failed: true, transient: true},
+		{status: http.StatusInternalServerError, failed: true, transient: true},
+		{status: http.StatusBadGateway, failed: true, transient: true},
+		{status: http.StatusServiceUnavailable, failed: true, transient: true},
+		{status: http.StatusGatewayTimeout, failed: true, transient: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.status), func(t *testing.T) {
+			err := checkStatus(&http.Response{
+				StatusCode: tt.status,
+				Status:     strconv.Itoa(tt.status) + " " + http.StatusText(tt.status),
+			})
+
+			if failed := err != nil; failed != tt.failed {
+				t.Fatalf("checkStatus() error = %v, want failed %v", err, tt.failed)
+			}
+
+			if !tt.failed {
+				return
+			}
+
+			if !errors.Is(err, ErrUnexpectedStatus) {
+				t.Errorf("checkStatus() error = %v, want %v", err, ErrUnexpectedStatus)
+			}
+
+			if transient := errors.As(err, &transientError{}); transient != tt.transient {
+				t.Errorf("checkStatus() transient = %v, want %v", transient, tt.transient)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package ingester
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+)
+
+// listen holds a dedicated connection listening on database.SourcesChannel
+// and wakes the Run loop for every notification until ctx is cancelled. The
+// Run loop polls while the connection is down, it is reopened every poll
+// interval.
+func (i *Ingester) listen(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := i.receiveNotifications(ctx)
+		i.listening.Store(false)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.WarnContext(ctx, "Not receiving source notifications, polling for due sources",
+			slog.Duration("poll_interval", i.opts.PollInterval),
+			slog.String("error", err.Error()),
+		)
+
+		// The loop may be sleeping until a far away due time, make it
+		// switch to polling right away.
+		i.signal()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(i.opts.PollInterval):
+		}
+	}
+}
+
+func (i *Ingester) receiveNotifications(ctx context.Context) error {
+	conn, err := i.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The connection keeps listening if it is returned to the pool, so it is
+	// closed instead and the pool replaces it.
+	defer func() {
+		conn.Conn().Close(context.WithoutCancel(ctx))
+		conn.Release()
+	}()
+
+	_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{database.SourcesChannel}.Sanitize())
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "Listening for source notifications", slog.String("channel", database.SourcesChannel))
+	i.listening.Store(true)
+
+	// Notifications sent before LISTEN took effect were missed, look for due
+	// sources again now that none will be.
+	i.signal()
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		slog.DebugContext(ctx, "Source changed", slog.String("source_id", notification.Payload))
+		i.signal()
+	}
+}
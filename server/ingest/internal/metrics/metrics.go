@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "prophet_ingest"
+
+// countTimeout bounds the queries run while a scrape is being served.
+const countTimeout = 5 * time.Second
+
+var (
+	FetchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "fetch_duration_seconds",
+		Help:      "Time taken to download and parse a source's feed.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"source"})
+
+	FetchResponses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fetch_responses_total",
+		Help:      "Feed responses by HTTP status code, requests that never got a response are counted with status error.",
+	}, []string{"source", "status"})
+
+	FetchRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fetch_retries_total",
+		Help:      "Fetches retried after a network error or a 5xx or 429 response.",
+	}, []string{"source"})
+
+	FetchedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fetched_bytes_total",
+		Help:      "Bytes of feed bodies read.",
+	}, []string{"source"})
+
+	ParsedRows = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "parsed_rows_total",
+		Help:      "Feed entries that parsed into an ip address.",
+	}, []string{"source"})
+
+	InvalidRows = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "invalid_rows_total",
+		Help:      "Feed entries that were rejected because they did not hold a valid ip address.",
+	}, []string{"source"})
+
+	NodesAdded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "nodes_added_total",
+		Help:      "Nodes that appeared in a source's snapshot.",
+	}, []string{"source"})
+
+	NodesRemoved = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "nodes_removed_total",
+		Help:      "Nodes that disappeared from a source's snapshot.",
+	}, []string{"source"})
+
+	Runs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "runs_total",
+		Help:      "Finished ingestions by result, one of success, unchanged or failure.",
+	}, []string{"source", "result"})
+
+	SourcePauses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "source_pauses_total",
+		Help:      "Failed ingestions that left the source paused by the circuit breaker.",
+	}, []string{"source"})
+
+	GcPasses = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "gc_passes_total",
+		Help:      "Completed garbage collection passes.",
+	})
+
+	GcMarkedRows = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "gc_marked_rows_total",
+		Help:      "Node rows stamped as superseded.",
+	})
+
+	GcReclaimedRows = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "gc_reclaimed_rows_total",
+		Help:      "Superseded node rows deleted.",
+	})
+)
+
+// RegisterAggregatedNodes registers the aggregated node gauge. count runs on
+// every scrape so the gauge also follows sources stopped or deleted through
+// the api, a failed count is reported as NaN.
+func RegisterAggregatedNodes(count func(ctx context.Context) (int64, error)) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "aggregated_nodes",
+		Help:      "Distinct ip addresses currently visible across every source.",
+	}, func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), countTimeout)
+		defer cancel()
+
+		n, err := count(ctx)
+		if err != nil {
+			slog.Warn("Unable to count aggregated nodes", slog.String("error", err.Error()))
+			return math.NaN()
+		}
+
+		return float64(n)
+	})
+}
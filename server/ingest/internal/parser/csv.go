@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+)
+
+const (
+	FormatCsv = "csv"
+)
+
+type CsvParser struct {
+	column int
+	header bool
+}
+
+func NewCsvParser(opts Options) (Parser, error) {
+	if opts.Column < 0 {
+		return nil, fmt.Errorf("csv column must not be negative, got %d", opts.Column)
+	}
+
+	return &CsvParser{
+		column: opts.Column,
+		header: opts.Header,
+	}, nil
+}
+
+// Parse implements Parser.
+func (p *CsvParser) Parse(r io.Reader) (Result, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	if p.header {
+		if _, err := reader.Read(); err != nil && !errors.Is(err, io.EOF) {
+			return Result{}, err
+		}
+	}
+
+	result := Result{Addrs: make([]netip.Addr, 0)}
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Result{}, err
+		}
+
+		if p.column >= len(row) {
+			result.Rejected++
+			continue
+		}
+
+		addr, err := ParseAddr(row[p.column])
+		if err != nil {
+			result.Rejected++
+			continue
+		}
+
+		result.Addrs = append(result.Addrs, addr)
+	}
+
+	return result, nil
+}
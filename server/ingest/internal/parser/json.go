@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+
+	"github.com/jhamill34/prophet-security-takehome/server/ingest/pkg/jsonpath"
+)
+
+const (
+	FormatJson = "json"
+)
+
+// JsonParser extracts addresses from a JSON document using the subset of
+// JSONPath described in the jsonpath package.
+type JsonParser struct {
+	path jsonpath.Path
+}
+
+func NewJsonParser(opts Options) (Parser, error) {
+	if opts.Path == "" {
+		return nil, fmt.Errorf("%w: json format requires a path", jsonpath.ErrInvalidPath)
+	}
+
+	path, err := jsonpath.Compile(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JsonParser{path}, nil
+}
+
+// Parse implements Parser.
+func (p *JsonParser) Parse(r io.Reader) (Result, error) {
+	var document any
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return Result{}, err
+	}
+
+	values := p.path.Select(document)
+
+	result := Result{Addrs: make([]netip.Addr, 0, len(values))}
+	for _, v := range values {
+		str, ok := v.(string)
+		if !ok {
+			result.Rejected++
+			continue
+		}
+
+		addr, err := ParseAddr(str)
+		if err != nil {
+			result.Rejected++
+			continue
+		}
+
+		result.Addrs = append(result.Addrs, addr)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jhamill34/prophet-security-takehome/server/ingest/pkg/jsonpath"
+)
+
+func TestJsonParse(t *testing.T) {
+	const onionoo = `{
+		"relays": [
+			{"exit_addresses": ["1.1.1.1", "2.2.2.2"]},
+			{"exit_addresses": []},
+			{"nickname": "no addresses"},
+			{"exit_addresses": ["3.3.3.3"]}
+		]
+	}`
+
+	tests := []struct {
+		name     string
+		path     string
+		input    string
+		addrs    []string
+		rejected int
+	}{
+		{
+			name:  "nested wildcards",
+			path:  "$.relays[*].exit_addresses[*]",
+			input: onionoo,
+			addrs: []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"},
+		},
+		{
+			name:  "index",
+			path:  "$.relays[0].exit_addresses[1]",
+			input: onionoo,
+			addrs: []string{"2.2.2.2"},
+		},
+		{
+			name:  "index out of range",
+			path:  "$.relays[9].exit_addresses[*]",
+			input: onionoo,
+			addrs: []string{},
+		},
+		{
+			name:  "root array",
+			path:  "$[*]",
+			input: `["1.1.1.1", "2001:db8::1"]`,
+			addrs: []string{"1.1.1.1", "2001:db8::1"},
+		},
+		{
+			name:  "root value",
+			path:  "$",
+			input: `"1.1.1.1"`,
+			addrs: []string{"1.1.1.1"},
+		},
+		{
+			name:  "quoted keys",
+			path:  `$["exit list"]['v4'][*]`,
+			input: `{"exit list": {"v4": ["1.1.1.1"]}}`,
+			addrs: []string{"1.1.1.1"},
+		},
+		{
+			name:     "non string and invalid values are rejected",
+			path:     "$.ips[*]",
+			input:    `{"ips": ["1.1.1.1", 42, {"ip": "2.2.2.2"}, "nope", null]}`,
+			addrs:    []string{"1.1.1.1"},
+			rejected: 4,
+		},
+		{
+			name:  "path that no longer matches",
+			path:  "$.relays[*].exit_addresses[*]",
+			input: `{"data": {"relays": []}}`,
+			addrs: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewJsonParser(Options{Path: tt.path})
+			if err != nil {
+				t.Fatalf("NewJsonParser() error = %v", err)
+			}
+
+			result, err := p.Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if want := addrs(t, tt.addrs...); !slices.Equal(result.Addrs, want) {
+				t.Errorf("Parse() addrs = %v, want %v", result.Addrs, want)
+			}
+
+			if result.Rejected != tt.rejected {
+				t.Errorf("Parse() rejected = %d, want %d", result.Rejected, tt.rejected)
+			}
+		})
+	}
+}
+
+func TestJsonParseInvalidDocument(t *testing.T) {
+	p, err := NewJsonParser(Options{Path: "$[*]"})
+	if err != nil {
+		t.Fatalf("NewJsonParser() error = %v", err)
+	}
+
+	_, err = p.Parse(strings.NewReader("<html>Service Unavailable</html>"))
+	if err == nil {
+		t.Error("Parse() error = nil, want an error")
+	}
+}
+
+func TestNewJsonParserInvalidPath(t *testing.T) {
+	for _, path := range []string{"", "$.a[", "$..a", "$.a['b]"} {
+		t.Run(path, func(t *testing.T) {
+			_, err := NewJsonParser(Options{Path: path})
+			if !errors.Is(err, jsonpath.ErrInvalidPath) {
+				t.Errorf("NewJsonParser(%q) error = %v, want %v", path, err, jsonpath.ErrInvalidPath)
+			}
+		})
+	}
+}
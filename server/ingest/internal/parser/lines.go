@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"bufio"
+	"io"
+	"net/netip"
+	"strings"
+)
+
+const (
+	FormatLines = "lines"
+)
+
+// LinesParser reads one address per line. Blank lines and anything following
+// a `#` are ignored.
+type LinesParser struct{}
+
+func NewLinesParser(opts Options) (Parser, error) {
+	return &LinesParser{}, nil
+}
+
+// Parse implements Parser.
+func (p *LinesParser) Parse(r io.Reader) (Result, error) {
+	scanner := bufio.NewScanner(r)
+
+	result := Result{Addrs: make([]netip.Addr, 0)}
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		addr, err := ParseAddr(fields[0])
+		if err != nil {
+			result.Rejected++
+			continue
+		}
+
+		result.Addrs = append(result.Addrs, addr)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
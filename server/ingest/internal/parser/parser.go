@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUnknownFormat = errors.New("Unknown feed format")
+)
+
+// Parser extracts the node addresses found in a feed document. Entries that
+// do not hold a valid address are counted and skipped rather than failing the
+// whole document, an error is only returned when the document itself cannot
+// be read.
+type Parser interface {
+	Parse(r io.Reader) (Result, error)
+}
+
+type Result struct {
+	Addrs    []netip.Addr
+	Rejected int
+}
+
+// Options are the per source settings stored alongside the format. Each
+// parser only reads the fields that apply to it.
+type Options struct {
+	Column int    `json:"column,omitempty"`
+	Header bool   `json:"header,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// ParseOptions decodes the raw format options stored on a source.
+func ParseOptions(raw []byte) (Options, error) {
+	var opts Options
+	if len(raw) == 0 {
+		return opts, nil
+	}
+
+	if err := json.Unmarshal(raw, &opts); err != nil {
+		return Options{}, err
+	}
+
+	return opts, nil
+}
+
+type Factory func(opts Options) (Parser, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register makes a parser available under the given format name. Registering
+// the same name twice replaces the previous factory.
+func Register(format string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[format] = factory
+}
+
+// New builds the parser registered for format using opts.
+func New(format string, opts Options) (Parser, error) {
+	registryMu.RLock()
+	factory, ok := registry[format]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+
+	return factory(opts)
+}
+
+func init() {
+	Register(FormatCsv, NewCsvParser)
+	Register(FormatLines, NewLinesParser)
+	Register(FormatTorExitAddresses, NewTorExitAddressesParser)
+	Register(FormatJson, NewJsonParser)
+}
+
+// ParseAddr accepts either a bare address or an address with a port, which
+// some feeds use to describe the relay endpoint.
+func ParseAddr(val string) (netip.Addr, error) {
+	val = strings.TrimSpace(val)
+
+	addr, err := netip.ParseAddr(val)
+	if err == nil {
+		return addr, nil
+	}
+
+	addrPort, portErr := netip.ParseAddrPort(val)
+	if portErr == nil {
+		return addrPort.Addr(), nil
+	}
+
+	return netip.Addr{}, err
+}
@@ -0,0 +1,200 @@
+package parser
+
+import (
+	"errors"
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func addrs(t *testing.T, values ...string) []netip.Addr {
+	t.Helper()
+
+	result := make([]netip.Addr, len(values))
+	for i, v := range values {
+		result[i] = netip.MustParseAddr(v)
+	}
+
+	return result
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		opts     Options
+		input    string
+		addrs    []string
+		rejected int
+	}{
+		{
+			name:   "csv first column",
+			format: FormatCsv,
+			input:  "1.1.1.1,a\n2.2.2.2,b\n",
+			addrs:  []string{"1.1.1.1", "2.2.2.2"},
+		},
+		{
+			name:   "csv header is skipped",
+			format: FormatCsv,
+			opts:   Options{Header: true},
+			input:  "ip,name\n1.1.1.1,a\n",
+			addrs:  []string{"1.1.1.1"},
+		},
+		{
+			name:     "csv header is parsed when not configured",
+			format:   FormatCsv,
+			input:    "ip,name\n1.1.1.1,a\n",
+			addrs:    []string{"1.1.1.1"},
+			rejected: 1,
+		},
+		{
+			name:   "csv header only",
+			format: FormatCsv,
+			opts:   Options{Header: true},
+			input:  "ip,name\n",
+			addrs:  []string{},
+		},
+		{
+			name:   "csv column",
+			format: FormatCsv,
+			opts:   Options{Column: 1},
+			input:  "a, 1.1.1.1\nb,2001:db8::1\n",
+			addrs:  []string{"1.1.1.1", "2001:db8::1"},
+		},
+		{
+			name:     "csv short and invalid rows are rejected",
+			format:   FormatCsv,
+			opts:     Options{Column: 1},
+			input:    "a\nb,not-an-ip\nc,3.3.3.3\n",
+			addrs:    []string{"3.3.3.3"},
+			rejected: 2,
+		},
+		{
+			name:   "csv address with port",
+			format: FormatCsv,
+			input:  "1.1.1.1:443\n[2001:db8::1]:443\n",
+			addrs:  []string{"1.1.1.1", "2001:db8::1"},
+		},
+		{
+			name:   "lines skips blank lines and comments",
+			format: FormatLines,
+			input:  "# header\n\n1.1.1.1\n  2.2.2.2 # trailing\n\t\n",
+			addrs:  []string{"1.1.1.1", "2.2.2.2"},
+		},
+		{
+			name:     "lines rejects invalid entries",
+			format:   FormatLines,
+			input:    "1.1.1.1\n<html>\nnope\n",
+			addrs:    []string{"1.1.1.1"},
+			rejected: 2,
+		},
+		{
+			name:   "tor exit addresses",
+			format: FormatTorExitAddresses,
+			input: strings.Join([]string{
+				"ExitNode 0011BD2485AD45D984EC4159C88FC066E5E3300E",
+				"Published 2024-01-01 00:00:00",
+				"LastStatus 2024-01-01 01:00:00",
+				"ExitAddress 1.1.1.1 2024-01-01 01:00:00",
+				"ExitAddress 2.2.2.2 2024-01-01 01:00:00",
+				"",
+			}, "\n"),
+			addrs: []string{"1.1.1.1", "2.2.2.2"},
+		},
+		{
+			name:     "tor rejects bad exit addresses",
+			format:   FormatTorExitAddresses,
+			input:    "ExitAddress\nExitAddress nope 2024-01-01\nExitAddress 3.3.3.3\n",
+			addrs:    []string{"3.3.3.3"},
+			rejected: 2,
+		},
+		{
+			name:   "tor ignores other records",
+			format: FormatTorExitAddresses,
+			input:  "ExitNode 1.1.1.1\nPublished 2.2.2.2\n",
+			addrs:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.format, tt.opts)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			result, err := p.Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if want := addrs(t, tt.addrs...); !slices.Equal(result.Addrs, want) {
+				t.Errorf("Parse() addrs = %v, want %v", result.Addrs, want)
+			}
+
+			if result.Rejected != tt.rejected {
+				t.Errorf("Parse() rejected = %d, want %d", result.Rejected, tt.rejected)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		opts    Options
+		wantErr error
+	}{
+		{name: "unknown format", format: "xml", wantErr: ErrUnknownFormat},
+		{name: "negative csv column", format: FormatCsv, opts: Options{Column: -1}},
+		{name: "json without path", format: FormatJson},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.format, tt.opts)
+			if err == nil {
+				t.Fatal("New() error = nil, want an error")
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	opts, err := ParseOptions([]byte(`{"column": 2, "header": true, "path": "$.a"}`))
+	if err != nil {
+		t.Fatalf("ParseOptions() error = %v", err)
+	}
+
+	if want := (Options{Column: 2, Header: true, Path: "$.a"}); opts != want {
+		t.Errorf("ParseOptions() = %+v, want %+v", opts, want)
+	}
+
+	opts, err = ParseOptions(nil)
+	if err != nil || opts != (Options{}) {
+		t.Errorf("ParseOptions(nil) = %+v, %v, want zero options", opts, err)
+	}
+
+	_, err = ParseOptions([]byte(`{`))
+	if err == nil {
+		t.Error("ParseOptions() error = nil, want an error")
+	}
+}
+
+func TestParseCsvMalformed(t *testing.T) {
+	p, err := New(FormatCsv, Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	_, err = p.Parse(strings.NewReader("\"1.1.1.1\n"))
+	if err == nil {
+		t.Error("Parse() error = nil, want an error for an unterminated quote")
+	}
+}
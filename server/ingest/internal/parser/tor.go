@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"bufio"
+	"io"
+	"net/netip"
+	"strings"
+)
+
+const (
+	FormatTorExitAddresses = "tor-exit-addresses"
+)
+
+// TorExitAddressesParser reads the Tor Project's exit-addresses document
+// (https://check.torproject.org/exit-addresses). Each relay is described by an
+// `ExitNode` record followed by one or more `ExitAddress` lines, only the
+// latter are of interest here.
+type TorExitAddressesParser struct{}
+
+func NewTorExitAddressesParser(opts Options) (Parser, error) {
+	return &TorExitAddressesParser{}, nil
+}
+
+// Parse implements Parser.
+func (p *TorExitAddressesParser) Parse(r io.Reader) (Result, error) {
+	scanner := bufio.NewScanner(r)
+
+	result := Result{Addrs: make([]netip.Addr, 0)}
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "ExitAddress" {
+			continue
+		}
+
+		if len(fields) < 2 {
+			result.Rejected++
+			continue
+		}
+
+		addr, err := ParseAddr(fields[1])
+		if err != nil {
+			result.Rejected++
+			continue
+		}
+
+		result.Addrs = append(result.Addrs, addr)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package jsonpath
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPath = errors.New("Invalid JSON path")
+)
+
+// Path is a compiled subset of JSONPath: `$` for the root, `.key` or
+// `["key"]` for object members, `[n]` for array indexes and `[*]` (or `.*`) to
+// visit every element. For example the Onionoo details document can be read
+// with `$.relays[*].exit_addresses[*]`.
+type Path struct {
+	segments []segment
+}
+
+type segment struct {
+	key      string
+	index    int
+	wildcard bool
+	isIndex  bool
+}
+
+// Compile parses path, the leading `$` may be omitted.
+func Compile(path string) (Path, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+
+	segments := make([]segment, 0)
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if strings.HasPrefix(rest, ".") {
+				return Path{}, fmt.Errorf("%w: recursive descent is not supported in %q", ErrInvalidPath, path)
+			}
+
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+
+			key := rest[:end]
+			rest = rest[end:]
+			if key == "" {
+				return Path{}, fmt.Errorf("%w: empty key in %q", ErrInvalidPath, path)
+			}
+
+			if key == "*" {
+				segments = append(segments, segment{wildcard: true})
+			} else {
+				segments = append(segments, segment{key: key})
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return Path{}, fmt.Errorf("%w: unterminated bracket in %q", ErrInvalidPath, path)
+			}
+
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			if inner == "*" {
+				segments = append(segments, segment{wildcard: true})
+				continue
+			}
+
+			if key, ok := unquote(inner); ok {
+				segments = append(segments, segment{key: key})
+				continue
+			}
+
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return Path{}, fmt.Errorf("%w: %q is not a quoted key, an index or * in %q", ErrInvalidPath, inner, path)
+			}
+			segments = append(segments, segment{index: index, isIndex: true})
+		default:
+			if len(segments) > 0 {
+				return Path{}, fmt.Errorf("%w: unexpected %q in %q", ErrInvalidPath, rest[0], path)
+			}
+
+			// Allow the leading `$.` to be omitted, e.g. `relays[*].ip`
+			rest = "." + rest
+		}
+	}
+
+	return Path{segments}, nil
+}
+
+// unquote accepts a key in single or double quotes, without escapes.
+func unquote(inner string) (string, bool) {
+	if len(inner) < 2 {
+		return "", false
+	}
+
+	quote := inner[0]
+	if (quote != '"' && quote != '\'') || inner[len(inner)-1] != quote {
+		return "", false
+	}
+
+	return inner[1 : len(inner)-1], true
+}
+
+// Select returns every value in document that the path points to. Members and
+// indexes that do not exist are skipped.
+func (p Path) Select(document any) []any {
+	values := []any{document}
+	for _, s := range p.segments {
+		values = s.apply(values)
+	}
+
+	return values
+}
+
+func (s segment) apply(values []any) []any {
+	next := make([]any, 0, len(values))
+	for _, v := range values {
+		switch node := v.(type) {
+		case map[string]any:
+			if s.wildcard {
+				for _, child := range node {
+					next = append(next, child)
+				}
+			} else if child, ok := node[s.key]; ok && !s.isIndex {
+				next = append(next, child)
+			}
+		case []any:
+			if s.wildcard {
+				next = append(next, node...)
+			} else if s.isIndex && s.index < len(node) {
+				next = append(next, node[s.index])
+			}
+		}
+	}
+
+	return next
+}
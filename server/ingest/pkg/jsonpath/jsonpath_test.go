@@ -0,0 +1,109 @@
+package jsonpath
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		path     string
+		segments []segment
+	}{
+		{path: "$", segments: []segment{}},
+		{path: "", segments: []segment{}},
+		{path: "$.a", segments: []segment{{key: "a"}}},
+		{path: "a.b", segments: []segment{{key: "a"}, {key: "b"}}},
+		{path: "a[0]", segments: []segment{{key: "a"}, {index: 0, isIndex: true}}},
+		{path: " $.a ", segments: []segment{{key: "a"}}},
+		{path: "$.*", segments: []segment{{wildcard: true}}},
+		{path: "$[*]", segments: []segment{{wildcard: true}}},
+		{path: "$[12]", segments: []segment{{index: 12, isIndex: true}}},
+		{path: `$["a.b"]`, segments: []segment{{key: "a.b"}}},
+		{path: `$['a b']`, segments: []segment{{key: "a b"}}},
+		{path: `$[""]`, segments: []segment{{key: ""}}},
+		{
+			path: "$.relays[*].exit_addresses[*]",
+			segments: []segment{
+				{key: "relays"},
+				{wildcard: true},
+				{key: "exit_addresses"},
+				{wildcard: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := Compile(tt.path)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(path.segments, tt.segments) {
+				t.Errorf("Compile() = %+v, want %+v", path.segments, tt.segments)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []string{
+		"$.",
+		"$.a.",
+		"$.a..b",
+		"$..a",
+		"$.a[",
+		"$.a[0",
+		"$[]",
+		"$[-1]",
+		"$[a]",
+		"$['a\"]",
+		"$['a]",
+		"$[0]a",
+	}
+
+	for _, path := range tests {
+		t.Run(path, func(t *testing.T) {
+			_, err := Compile(path)
+			if !errors.Is(err, ErrInvalidPath) {
+				t.Errorf("Compile(%q) error = %v, want %v", path, err, ErrInvalidPath)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	document := map[string]any{
+		"a": []any{"x", map[string]any{"b": "y"}},
+		"c": "z",
+	}
+
+	tests := []struct {
+		path string
+		want []any
+	}{
+		{path: "$.a[0]", want: []any{"x"}},
+		{path: "$.a[1].b", want: []any{"y"}},
+		{path: "$.a[*].b", want: []any{"y"}},
+		{path: "$.c", want: []any{"z"}},
+		{path: "$.c[0]", want: []any{}},
+		{path: "$.a.b", want: []any{}},
+		{path: "$.missing", want: []any{}},
+		{path: "$[0]", want: []any{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, err := Compile(tt.path)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+
+			if got := path.Select(document); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,32 +1,81 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
-)
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-const (
-	PORT = 3334
+	"github.com/jhamill34/prophet-security-takehome/server/config"
 )
 
 func main() {
-	fs := http.FileServer(http.Dir("./static"))
+	configPath := flag.String("config", os.Getenv(config.PathEnv), "Path to the yaml config file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	slog.SetDefault(cfg.Log.Logger())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fs := http.FileServer(http.Dir(cfg.Mock.StaticDir))
 	mux := http.NewServeMux()
 	mux.Handle("/", fs)
 
-	server := NewServer(PORT, mux)
-	Run(server)
+	server := NewServer(cfg.Mock.ListenAddress, mux)
+	err = Run(ctx, server, cfg.Mock.ShutdownTimeout)
+	if err != nil {
+		slog.Error("Server stopped with an error", slog.String("error", err.Error()))
+		stop()
+		os.Exit(1)
+	}
 }
 
-func NewServer(port uint16, handler http.Handler) *http.Server {
+func NewServer(addr string, handler http.Handler) *http.Server {
 	return &http.Server{
-		Addr:    fmt.Sprintf("127.0.0.1:%d", port),
+		Addr:    addr,
 		Handler: handler,
 	}
 }
 
-func Run(server *http.Server) {
-	slog.Info("Starting server")
-	server.ListenAndServe()
+// Run serves until ctx is cancelled and then gives in-flight requests up to
+// timeout to finish.
+func Run(ctx context.Context, server *http.Server, timeout time.Duration) error {
+	slog.Info("Starting server", slog.String("listen_address", server.Addr))
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := server.Shutdown(shutdownCtx)
+	if err != nil {
+		return errors.Join(err, server.Close())
+	}
+
+	slog.Info("Server stopped")
+	return nil
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jhamill34/prophet-security-takehome/server/config"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+	"github.com/jhamill34/prophet-security-takehome/server/web/internal/auth"
+	"github.com/jhamill34/prophet-security-takehome/server/web/internal/db"
+)
+
+var (
+	ErrActiveKeyExists = errors.New("An active API key already exists, use the /admin/keys endpoints to create more")
+)
+
+// bootstrap mints the first admin key with every scope. It refuses to run once
+// any active key exists so it can not be used to escalate access later on,
+// further keys should be created through the /admin/keys endpoints.
+func bootstrap(args []string) {
+	flags := flag.NewFlagSet("bootstrap", flag.ExitOnError)
+	name := flags.String("name", "admin", "Name of the admin API key")
+	configPath := flags.String("config", os.Getenv(config.PathEnv), "Path to the yaml config file")
+	flags.Parse(args)
+
+	cfg := loadConfig(*configPath)
+
+	plaintext, prefix, err := auth.GenerateKey()
+	if err != nil {
+		exitWithError(err)
+	}
+
+	ctx := context.Background()
+	pool := db.NewDatabase(ctx, cfg.Database)
+	defer pool.Close()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		exitWithError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	queries := database.New(tx)
+
+	err = queries.LockApiKeyBootstrap(ctx)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	_, err = queries.CreateBootstrapApiKey(ctx, database.CreateBootstrapApiKeyParams{
+		Name:    *name,
+		Prefix:  prefix,
+		KeyHash: auth.HashKey(plaintext),
+		Scopes:  auth.AllScopes,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		exitWithError(ErrActiveKeyExists)
+	}
+
+	if err != nil {
+		exitWithError(err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	fmt.Println(plaintext)
+}
+
+// exitWithError reports err the same way loadConfig does, the open transaction
+// is rolled back when the process exits and its connection closes.
+func exitWithError(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
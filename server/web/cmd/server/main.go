@@ -2,45 +2,83 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httplog/v2"
 	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
+	"github.com/jhamill34/prophet-security-takehome/server/config"
 	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/secrets"
 	"github.com/jhamill34/prophet-security-takehome/server/web/internal/auth"
 	"github.com/jhamill34/prophet-security-takehome/server/web/internal/db"
+	"github.com/jhamill34/prophet-security-takehome/server/web/internal/metrics"
 	"github.com/jhamill34/prophet-security-takehome/server/web/internal/routes"
 	"github.com/jhamill34/prophet-security-takehome/server/web/internal/server"
 	nethttpmiddleware "github.com/oapi-codegen/nethttp-middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
-	queries := database.New(
-		db.NewDatabase(context.TODO(), "host=localhost port=5432 user=prophet-th password=prophet-th dbname=prophet-th sslmode=disable"),
-	)
+	if len(os.Args) > 1 && os.Args[1] == "bootstrap" {
+		bootstrap(os.Args[2:])
+		return
+	}
+
+	os.Exit(run())
+}
+
+// run serves the api until a shutdown signal arrives and returns the process
+// exit code, keeping deferred cleanup ahead of os.Exit.
+func run() int {
+	configPath := flag.String("config", os.Getenv(config.PathEnv), "Path to the yaml config file")
+	flag.Parse()
+
+	cfg := loadConfig(*configPath)
+	box := loadSecrets(cfg.Secrets)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	pool := db.NewDatabase(ctx, cfg.Database)
+	defer pool.Close()
+
+	queries := database.New(pool)
 
 	swagger, err := api.GetSwagger()
 	if err != nil {
 		panic(err)
 	}
 
+	// The validator matches the Host header against the spec's servers, which
+	// would reject requests made to any address other than the documented
+	// one, such as health checks from an orchestrator.
+	swagger.Servers = nil
+
 	validator := nethttpmiddleware.OapiRequestValidatorWithOptions(swagger, &nethttpmiddleware.Options{
 		Options: openapi3filter.Options{
 			AuthenticationFunc: auth.AuthValidator,
 		},
+		ErrorHandler:          auth.ValidationErrorHandler,
 		SilenceServersWarning: true,
 	})
 
 	logger := httplog.NewLogger(swagger.Info.Title, httplog.Options{
-		LogLevel:         slog.LevelDebug,
-		JSON:             false,
-		Concise:          false,
-		RequestHeaders:   true,
-		MessageFieldName: "message",
+		LogLevel:       cfg.Log.SlogLevel(),
+		JSON:           cfg.Log.JSON(),
+		Concise:        false,
+		RequestHeaders: true,
+		// Authorization and cookies are already redacted, API keys are not.
+		HideRequestHeaders: []string{auth.ApiKeyHeader},
+		MessageFieldName:   "message",
 		Tags: map[string]string{
 			"version": swagger.Info.Version,
 			"env":     "development",
@@ -51,10 +89,19 @@ func main() {
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.RequestID)
 	router.Use(RequestIdInResponseMiddleware)
+	router.Use(metrics.Middleware)
 	router.Use(httplog.RequestLogger(logger))
-	router.Use(validator)
 
-	serverRoutes := api.NewStrictHandlerWithOptions(routes.NewServerRoutes(queries), []api.StrictMiddlewareFunc{}, api.StrictHTTPServerOptions{
+	router.Handle("/metrics", promhttp.Handler())
+
+	// Middleware on a group only runs once a route has matched, so rejected
+	// requests are still recorded against their route pattern.
+	apiRouter := router.Group(func(r chi.Router) {
+		r.Use(auth.ApiKeyMiddleware(queries))
+		r.Use(validator)
+	})
+
+	serverRoutes := api.NewStrictHandlerWithOptions(routes.NewServerRoutes(pool, box), []api.StrictMiddlewareFunc{}, api.StrictHTTPServerOptions{
 		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
 			oplog := httplog.LogEntry(r.Context())
 			oplog.Error(
@@ -65,14 +112,56 @@ func main() {
 		},
 	})
 
-	handler := api.HandlerWithOptions(serverRoutes, api.ChiServerOptions{
-		BaseRouter: router,
+	api.HandlerWithOptions(serverRoutes, api.ChiServerOptions{
+		BaseRouter: apiRouter,
 	})
 
-	s := server.NewHttpServer(3333, handler)
+	s := server.NewHttpServer(cfg.Web.ListenAddress, router)
+
+	// A second signal while draining restores the default behaviour and
+	// kills the process straight away.
+	go func() {
+		<-ctx.Done()
+		logger.Logger.Info("Shutting down, waiting for in-flight requests", slog.Duration("shutdown_timeout", cfg.Web.ShutdownTimeout))
+		stop()
+	}()
+
+	logger.Logger.Info("Starting Server", slog.String("listen_address", cfg.Web.ListenAddress))
+	err = server.Serve(ctx, s, cfg.Web.ShutdownTimeout)
+	if err != nil {
+		logger.Logger.Error("Server stopped with an error", slog.String("error", err.Error()))
+		return 1
+	}
+
+	logger.Logger.Info("Server stopped")
+	return 0
+}
+
+func loadConfig(path string) config.Config {
+	cfg, err := config.Load(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	return cfg
+}
+
+// loadSecrets returns nil when no key is configured, sources can then not be
+// given credentials.
+func loadSecrets(cfg config.SecretsConfig) *secrets.Box {
+	key, err := cfg.DecodeKey()
+	if err == nil && key == nil {
+		return nil
+	}
+
+	box, err := secrets.NewBox(key)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	logger.Logger.Info("Starting Server")
-	s.ListenAndServe()
+	return box
 }
 
 func RequestIdInResponseMiddleware(next http.Handler) http.Handler {
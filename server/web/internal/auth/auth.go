@@ -2,10 +2,156 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/go-chi/httplog/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
 )
 
+const ApiKeyHeader = "X-Api-Key"
+
+const (
+	ScopeNodesRead      = "nodes:read"
+	ScopeAllowlistRead  = "allowlist:read"
+	ScopeAllowlistWrite = "allowlist:write"
+	ScopeSourcesRead    = "sources:read"
+	ScopeSourcesAdmin   = "sources:admin"
+	ScopeKeysAdmin      = "keys:admin"
+	ScopeAuditRead      = "audit:read"
+)
+
+// AllScopes is every scope a key can be granted.
+var AllScopes = []string{
+	ScopeNodesRead,
+	ScopeAllowlistRead,
+	ScopeAllowlistWrite,
+	ScopeSourcesRead,
+	ScopeSourcesAdmin,
+	ScopeKeysAdmin,
+	ScopeAuditRead,
+}
+
+var (
+	ErrUnauthenticated = errors.New("Missing or invalid API key")
+	ErrMissingScope    = errors.New("API key is missing the required scope")
+)
+
+type principalKey struct{}
+
+// Principal is the identity attached to a request that presented a valid API key.
+type Principal struct {
+	KeyId  int32
+	Name   string
+	Scopes []string
+}
+
+// HasScope reports whether the principal was granted scope. A write or admin
+// scope also grants the read scope of the same resource, and admin grants write.
+func (p *Principal) HasScope(scope string) bool {
+	resource, level, _ := strings.Cut(scope, ":")
+
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+
+		grantedResource, grantedLevel, _ := strings.Cut(granted, ":")
+		if grantedResource != resource {
+			continue
+		}
+
+		switch level {
+		case "read":
+			if grantedLevel == "write" || grantedLevel == "admin" {
+				return true
+			}
+		case "write":
+			if grantedLevel == "admin" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
+
+// ApiKeyMiddleware resolves the X-Api-Key header into a Principal on the request
+// context. Requests without a valid key are passed through untouched so that the
+// request validator can decide whether the operation requires one.
+func ApiKeyMiddleware(queries *database.Queries) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(ApiKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			apiKey, err := queries.GetActiveApiKeyByHash(ctx, HashKey(key))
+			if errors.Is(err, pgx.ErrNoRows) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err != nil {
+				oplog := httplog.LogEntry(ctx)
+				oplog.Error("Unable to look up API key", slog.String("error", err.Error()))
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+
+			if err := queries.TouchApiKey(ctx, apiKey.ID); err != nil {
+				oplog := httplog.LogEntry(ctx)
+				oplog.Warn("Unable to record API key usage", slog.String("error", err.Error()))
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(ctx, &Principal{
+				KeyId:  apiKey.ID,
+				Name:   apiKey.Name,
+				Scopes: apiKey.Scopes,
+			})))
+		})
+	}
+}
+
+// AuthValidator checks the principal attached by ApiKeyMiddleware against the
+// scopes an operation declares in the spec.
 func AuthValidator(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+	principal, ok := PrincipalFromContext(input.RequestValidationInput.Request.Context())
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	for _, scope := range input.Scopes {
+		if !principal.HasScope(scope) {
+			return ErrMissingScope
+		}
+	}
+
 	return nil
 }
+
+// ValidationErrorHandler writes validator failures, upgrading security failures
+// caused by a missing scope from a 401 to a 403.
+func ValidationErrorHandler(w http.ResponseWriter, message string, statusCode int) {
+	if statusCode == http.StatusUnauthorized && strings.Contains(message, ErrMissingScope.Error()) {
+		statusCode = http.StatusForbidden
+	}
+
+	http.Error(w, message, statusCode)
+}
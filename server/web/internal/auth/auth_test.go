@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
+)
+
+// grants lists every scope a key holding the scope on the left passes.
+var grants = map[string][]string{
+	ScopeNodesRead:      {ScopeNodesRead},
+	ScopeAllowlistRead:  {ScopeAllowlistRead},
+	ScopeAllowlistWrite: {ScopeAllowlistWrite, ScopeAllowlistRead},
+	ScopeSourcesRead:    {ScopeSourcesRead},
+	ScopeSourcesAdmin:   {ScopeSourcesAdmin, ScopeSourcesRead},
+	ScopeKeysAdmin:      {ScopeKeysAdmin},
+	ScopeAuditRead:      {ScopeAuditRead},
+}
+
+func TestAllScopesMatchSpec(t *testing.T) {
+	swagger, err := api.GetSwagger()
+	if err != nil {
+		t.Fatalf("GetSwagger() error = %v", err)
+	}
+
+	var specScopes []string
+	for _, value := range swagger.Components.Schemas["ApiKeyScope"].Value.Enum {
+		specScopes = append(specScopes, value.(string))
+	}
+
+	got := slices.Clone(AllScopes)
+	slices.Sort(got)
+	slices.Sort(specScopes)
+	if !slices.Equal(got, specScopes) {
+		t.Errorf("AllScopes = %v, want the spec's ApiKeyScope enum %v", got, specScopes)
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	for _, scope := range AllScopes {
+		if _, ok := grants[scope]; !ok {
+			t.Fatalf("grants is missing %q, add the scopes it is expected to grant", scope)
+		}
+	}
+
+	for _, granted := range AllScopes {
+		for _, required := range AllScopes {
+			principal := &Principal{Scopes: []string{granted}}
+			want := slices.Contains(grants[granted], required)
+			if got := principal.HasScope(required); got != want {
+				t.Errorf("key with %q: HasScope(%q) = %v, want %v", granted, required, got, want)
+			}
+		}
+	}
+}
+
+func TestHasScopeCombinations(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []string
+		required string
+		want     bool
+	}{
+		{name: "no scopes", scopes: nil, required: ScopeNodesRead, want: false},
+		{name: "any granted scope matches", scopes: []string{ScopeNodesRead, ScopeAuditRead}, required: ScopeAuditRead, want: true},
+		{name: "read does not grant write", scopes: []string{ScopeAllowlistRead}, required: ScopeAllowlistWrite, want: false},
+		{name: "admin grants write", scopes: []string{"allowlist:admin"}, required: ScopeAllowlistWrite, want: true},
+		{name: "write does not grant admin", scopes: []string{ScopeAllowlistWrite}, required: "allowlist:admin", want: false},
+		{name: "other resource", scopes: []string{ScopeSourcesAdmin}, required: ScopeNodesRead, want: false},
+		{name: "resource prefix", scopes: []string{"node:read"}, required: ScopeNodesRead, want: false},
+		{name: "unknown level", scopes: []string{"nodes:owner"}, required: ScopeNodesRead, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal := &Principal{Scopes: tt.scopes}
+			if got := principal.HasScope(tt.required); got != tt.want {
+				t.Errorf("HasScope(%q) with %v = %v, want %v", tt.required, tt.scopes, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const keyPrefixBytes = 4
+const keySecretBytes = 32
+
+// GenerateKey returns a new plaintext API key along with the prefix used to
+// identify it in listings. Only the hash of the key should ever be stored.
+func GenerateKey() (key string, prefix string, err error) {
+	prefixBytes := make([]byte, keyPrefixBytes)
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", err
+	}
+
+	secretBytes := make([]byte, keySecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	key = prefix + "." + base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	return key, prefix, nil
+}
+
+func HashKey(key string) []byte {
+	sum := sha256.Sum256([]byte(key))
+	return sum[:]
+}
@@ -3,11 +3,24 @@ package db
 import (
 	"context"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jhamill34/prophet-security-takehome/server/config"
 )
 
-func NewDatabase(ctx context.Context, connection string) *pgx.Conn {
-	db, err := pgx.Connect(ctx, connection)
+// NewDatabase opens a connection pool and makes sure the database is reachable
+// so that a bad DSN fails at startup rather than on the first request.
+func NewDatabase(ctx context.Context, cfg config.DatabaseConfig) *pgxpool.Pool {
+	poolConfig, err := cfg.PoolConfig()
+	if err != nil {
+		panic(err)
+	}
+
+	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	err = db.Ping(ctx)
 	if err != nil {
 		panic(err)
 	}
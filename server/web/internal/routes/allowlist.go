@@ -50,35 +50,61 @@ func (s *ServerRoutes) AddToAllowlist(ctx context.Context, request api.AddToAllo
 		return api.AddToAllowlist400TextResponse(err.Error()), nil
 	}
 
-	dbResult, err := s.queries.AddToAllowlist(ctx, database.AddToAllowlistParams{
-		Cidr:   ipAddr,
-		ListID: int32(list.Id),
+	var entry api.AllowlistEntryItem
+	err = s.withTx(ctx, func(queries *database.Queries) error {
+		dbResult, err := queries.AddToAllowlist(ctx, database.AddToAllowlistParams{
+			Cidr:   ipAddr,
+			ListID: int32(list.Id),
+		})
+		if err != nil {
+			return err
+		}
+
+		entry = api.AllowlistEntryItem{
+			Id:          int(dbResult.ID),
+			Cidr:        dbResult.Cidr.String(),
+			AllowlistId: int(dbResult.ListID),
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "allowlist_entry.add",
+			resourceType: api.AuditResourceTypeAllowlistEntry,
+			resourceId:   entry.Id,
+			after:        entry,
+		})
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	entry := api.AllowlistEntryItem{
-		Id:          int(dbResult.ID),
-		Cidr:        dbResult.Cidr.String(),
-		AllowlistId: int(dbResult.ListID),
-	}
-
 	return api.AddToAllowlist201JSONResponse(entry), nil
 }
 
 // CreateAllowlist implements api.StrictServerInterface.
 func (s *ServerRoutes) CreateAllowlist(ctx context.Context, request api.CreateAllowlistRequestObject) (api.CreateAllowlistResponseObject, error) {
-	dbResult, err := s.queries.CreateAllowList(ctx, request.Body.Name)
+	var entry api.AllowlistEntry
+	err := s.withTx(ctx, func(queries *database.Queries) error {
+		dbResult, err := queries.CreateAllowList(ctx, request.Body.Name)
+		if err != nil {
+			return err
+		}
+
+		entry = api.AllowlistEntry{
+			Id:   int(dbResult.ID),
+			Name: dbResult.Name,
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "allowlist.create",
+			resourceType: api.AuditResourceTypeAllowlist,
+			resourceId:   entry.Id,
+			after:        entry,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	entry := api.AllowlistEntry{
-		Id:   int(dbResult.ID),
-		Name: dbResult.Name,
-	}
-
 	return api.CreateAllowlist201JSONResponse(entry), nil
 }
 
@@ -92,7 +118,19 @@ func (s *ServerRoutes) DeleteAllowList(ctx context.Context, request api.DeleteAl
 		return nil, err
 	}
 
-	err = s.queries.DeleteAllowList(ctx, int32(list.Id))
+	err = s.withTx(ctx, func(queries *database.Queries) error {
+		err := queries.DeleteAllowList(ctx, int32(list.Id))
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "allowlist.delete",
+			resourceType: api.AuditResourceTypeAllowlist,
+			resourceId:   list.Id,
+			before:       list,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -176,9 +214,30 @@ func (s *ServerRoutes) RemoveFromAllowlist(ctx context.Context, request api.Remo
 		return nil, err
 	}
 
-	err = s.queries.RemoveFromAllowlist(ctx, database.RemoveFromAllowlistParams{
-		ListID: int32(list.Id),
-		ID:     int32(request.EntryId),
+	err = s.withTx(ctx, func(queries *database.Queries) error {
+		dbResult, err := queries.RemoveFromAllowlist(ctx, database.RemoveFromAllowlistParams{
+			ListID: int32(list.Id),
+			ID:     int32(request.EntryId),
+		})
+		// Removing an entry that is already gone is not a change worth recording
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "allowlist_entry.remove",
+			resourceType: api.AuditResourceTypeAllowlistEntry,
+			resourceId:   int(dbResult.ID),
+			before: api.AllowlistEntryItem{
+				Id:          int(dbResult.ID),
+				Cidr:        dbResult.Cidr.String(),
+				AllowlistId: int(dbResult.ListID),
+			},
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -0,0 +1,162 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+	"github.com/jhamill34/prophet-security-takehome/server/web/internal/auth"
+)
+
+const anonymousActor = "anonymous"
+
+type auditRecord struct {
+	operation    string
+	resourceType api.AuditResourceType
+	resourceId   int
+	before       any
+	after        any
+}
+
+// recordAudit writes an audit record for a mutation attributed to the principal
+// and request id on ctx. It should run on the same transaction as the mutation.
+func recordAudit(ctx context.Context, queries *database.Queries, record auditRecord) error {
+	params := database.InsertAuditLogParams{
+		Actor:        anonymousActor,
+		RequestID:    middleware.GetReqID(ctx),
+		Operation:    record.operation,
+		ResourceType: string(record.resourceType),
+		ResourceID:   strconv.Itoa(record.resourceId),
+	}
+
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		params.Actor = principal.Name
+		params.ActorKeyID = pgtype.Int4{Int32: principal.KeyId, Valid: true}
+	}
+
+	var err error
+	if record.before != nil {
+		params.Before, err = json.Marshal(record.before)
+		if err != nil {
+			return err
+		}
+	}
+
+	if record.after != nil {
+		params.After, err = json.Marshal(record.after)
+		if err != nil {
+			return err
+		}
+	}
+
+	return queries.InsertAuditLog(ctx, params)
+}
+
+func auditEntryFromModel(record database.AuditLog) (api.AuditEntry, error) {
+	result := api.AuditEntry{
+		Id:           int(record.ID),
+		Actor:        record.Actor,
+		RequestId:    record.RequestID,
+		Operation:    record.Operation,
+		ResourceType: api.AuditResourceType(record.ResourceType),
+		ResourceId:   record.ResourceID,
+		OccurredAt:   record.OccurredAt.Time.Format(time.RFC3339),
+	}
+
+	if record.ActorKeyID.Valid {
+		result.ActorKeyId = Ptr(int(record.ActorKeyID.Int32))
+	}
+
+	if len(record.Before) > 0 {
+		var before map[string]interface{}
+		if err := json.Unmarshal(record.Before, &before); err != nil {
+			return api.AuditEntry{}, err
+		}
+		result.Before = &before
+	}
+
+	if len(record.After) > 0 {
+		var after map[string]interface{}
+		if err := json.Unmarshal(record.After, &after); err != nil {
+			return api.AuditEntry{}, err
+		}
+		result.After = &after
+	}
+
+	return result, nil
+}
+
+// ListAuditLog implements api.StrictServerInterface.
+func (s *ServerRoutes) ListAuditLog(ctx context.Context, request api.ListAuditLogRequestObject) (api.ListAuditLogResponseObject, error) {
+	limit := DefaultValue(request.Params.Limit, 10)
+	if limit < 1 {
+		return api.ListAuditLog400TextResponse(ErrInvalidLimit.Error()), nil
+	}
+
+	// Records are listed newest first so the cursor counts down from the end.
+	before, err := strconv.ParseInt(DefaultValue(request.Params.After, strconv.FormatInt(math.MaxInt64, 10)), 10, 64)
+	if err != nil {
+		return api.ListAuditLog400TextResponse(err.Error()), nil
+	}
+
+	params := database.ListAuditLogParams{
+		BeforeID:   before,
+		MaxResults: int32(limit + 1),
+	}
+
+	if request.Params.Actor != nil {
+		params.Actor = pgtype.Text{String: *request.Params.Actor, Valid: true}
+	}
+
+	if request.Params.ResourceType != nil {
+		params.ResourceType = pgtype.Text{String: string(*request.Params.ResourceType), Valid: true}
+	}
+
+	if request.Params.ResourceId != nil {
+		params.ResourceID = pgtype.Text{String: *request.Params.ResourceId, Valid: true}
+	}
+
+	if request.Params.Since != nil {
+		params.Since = pgtype.Timestamp{Time: request.Params.Since.UTC(), Valid: true}
+	}
+
+	if request.Params.Until != nil {
+		params.Until = pgtype.Timestamp{Time: request.Params.Until.UTC(), Valid: true}
+	}
+
+	dbResult, err := s.queries.ListAuditLog(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	dbResult, hasMore := TrimPage(dbResult, limit)
+
+	result := make([]api.AuditEntry, len(dbResult))
+	for i, r := range dbResult {
+		result[i], err = auditEntryFromModel(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cursor := ""
+	if len(result) > 0 {
+		cursor = fmt.Sprintf("%d", result[len(result)-1].Id)
+	}
+
+	response := api.PaginatedAuditEntry{
+		Total:   len(result),
+		Cursor:  cursor,
+		HasMore: hasMore,
+		Data:    result,
+	}
+
+	return api.ListAuditLog200JSONResponse(response), nil
+}
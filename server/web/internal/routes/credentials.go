@@ -0,0 +1,126 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/secrets"
+)
+
+var (
+	ErrSecretsKeyMissing     = errors.New("Source credentials can not be stored until secrets.key is configured")
+	ErrConflictingAuthHeader = errors.New("Only one of credentials.bearer_token, credentials.basic_auth and an Authorization header may be given")
+	ErrInvalidHeader         = errors.New("Invalid header in credentials.headers")
+)
+
+// reservedHeaders are set by the http client or the ingester itself.
+var reservedHeaders = map[string]struct{}{
+	"Host":              {},
+	"Content-Length":    {},
+	"Transfer-Encoding": {},
+	"Connection":        {},
+	"If-None-Match":     {},
+	"If-Modified-Since": {},
+}
+
+// requestCredentials validates the credentials given in a request. They are
+// only sealed once the id of the source they belong to is known.
+func (s *ServerRoutes) requestCredentials(credentials api.SourceCredentials) (secrets.SourceCredentials, error) {
+	result := secrets.SourceCredentials{
+		Headers:     DefaultValue(credentials.Headers, nil),
+		BearerToken: DefaultValue(credentials.BearerToken, ""),
+	}
+
+	if credentials.BasicAuth != nil {
+		result.BasicAuth = &secrets.BasicAuth{
+			Username: credentials.BasicAuth.Username,
+			Password: credentials.BasicAuth.Password,
+		}
+	}
+
+	if result.IsEmpty() {
+		return result, nil
+	}
+
+	err := validateCredentials(result)
+	if err != nil {
+		return secrets.SourceCredentials{}, err
+	}
+
+	if s.secrets == nil {
+		return secrets.SourceCredentials{}, ErrSecretsKeyMissing
+	}
+
+	return result, nil
+}
+
+// sealCredentials encrypts credentials returned by requestCredentials for the
+// given source's row. Empty credentials return nil, removing them.
+func (s *ServerRoutes) sealCredentials(sourceId int32, credentials secrets.SourceCredentials) ([]byte, error) {
+	if credentials.IsEmpty() {
+		return nil, nil
+	}
+
+	return s.secrets.SealCredentials(sourceId, credentials)
+}
+
+func validateCredentials(credentials secrets.SourceCredentials) error {
+	authorizations := 0
+	if credentials.BearerToken != "" {
+		authorizations++
+	}
+
+	if credentials.BasicAuth != nil {
+		authorizations++
+	}
+
+	for name, value := range credentials.Headers {
+		if !isToken(name) || strings.ContainsAny(value, "\r\n\x00") {
+			return fmt.Errorf("%w: %q", ErrInvalidHeader, name)
+		}
+
+		canonical := http.CanonicalHeaderKey(name)
+		if _, ok := reservedHeaders[canonical]; ok {
+			return fmt.Errorf("%w: %q is set by the ingester", ErrInvalidHeader, name)
+		}
+
+		if canonical == "Authorization" {
+			authorizations++
+		}
+	}
+
+	if authorizations > 1 {
+		return ErrConflictingAuthHeader
+	}
+
+	return nil
+}
+
+// isToken reports whether name is a valid header field name (RFC 9110 token).
+func isToken(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// isCredentialsError reports whether err was caused by the request rather than
+// by sealing the credentials.
+func isCredentialsError(err error) bool {
+	return errors.Is(err, ErrSecretsKeyMissing) ||
+		errors.Is(err, ErrConflictingAuthHeader) ||
+		errors.Is(err, ErrInvalidHeader)
+}
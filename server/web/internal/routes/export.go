@@ -0,0 +1,149 @@
+package routes
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/httplog/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+)
+
+// ExportNodes implements api.StrictServerInterface.
+func (s *ServerRoutes) ExportNodes(ctx context.Context, request api.ExportNodesRequestObject) (api.ExportNodesResponseObject, error) {
+	format := DefaultValue(request.Params.Format, api.ExportNodesParamsFormatTxt)
+
+	var listId pgtype.Int4
+	if request.Params.AllowlistId != nil {
+		listId.Scan(int64(*request.Params.AllowlistId))
+	}
+
+	response := exportNodesResponse{
+		ctx:     ctx,
+		queries: s.queries,
+		format:  format,
+		params: database.ExportNodesParams{
+			ListID: listId,
+			Invert: DefaultValue(request.Params.Invert, false),
+		},
+	}
+
+	return response, nil
+}
+
+// exportNodesResponse writes rows to the client as they are read from the
+// database rather than building the whole document in memory first.
+type exportNodesResponse struct {
+	ctx     context.Context
+	queries *database.Queries
+	format  api.ExportNodesParamsFormat
+	params  database.ExportNodesParams
+}
+
+func (r exportNodesResponse) VisitExportNodesResponse(w http.ResponseWriter) error {
+	newWriter := txtNodeWriter
+	contentType := "text/plain"
+	switch r.format {
+	case api.ExportNodesParamsFormatCsv:
+		newWriter = csvNodeWriter
+		contentType = "text/csv"
+	case api.ExportNodesParamsFormatJsonl:
+		newWriter = jsonlNodeWriter
+		contentType = "application/x-ndjson"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"nodes.%s\"", r.format))
+	w.WriteHeader(http.StatusOK)
+
+	write, flush, err := newWriter(bufio.NewWriter(w))
+	if err == nil {
+		err = r.queries.ExportNodesEach(r.ctx, r.params, write)
+	}
+	if err == nil {
+		err = flush()
+	}
+
+	if err != nil {
+		// The status has already been sent, aborting the handler drops the
+		// connection so the client sees a truncated transfer instead of a
+		// list that silently looks complete.
+		oplog := httplog.LogEntry(r.ctx)
+		oplog.Error("Node export failed", slog.String("internal_error", err.Error()))
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil
+}
+
+type nodeWriter func(row database.ExportNodesRow) error
+
+func txtNodeWriter(w *bufio.Writer) (nodeWriter, func() error, error) {
+	write := func(row database.ExportNodesRow) error {
+		_, err := fmt.Fprintln(w, row.IpAddr.String())
+		return err
+	}
+
+	return write, w.Flush, nil
+}
+
+func csvNodeWriter(w *bufio.Writer) (nodeWriter, func() error, error) {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"ip_addr", "sources", "last_seen"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	write := func(row database.ExportNodesRow) error {
+		sources := make([]string, len(row.SourceIds))
+		for i, id := range row.SourceIds {
+			sources[i] = strconv.Itoa(int(id))
+		}
+
+		return writer.Write([]string{
+			row.IpAddr.String(),
+			strings.Join(sources, ";"),
+			row.LastSeen.Time.Format(time.RFC3339),
+		})
+	}
+
+	flush := func() error {
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+
+		return w.Flush()
+	}
+
+	return write, flush, nil
+}
+
+type exportedNode struct {
+	IpAddr   string  `json:"ip_addr"`
+	Sources  []int32 `json:"sources"`
+	LastSeen string  `json:"last_seen"`
+}
+
+func jsonlNodeWriter(w *bufio.Writer) (nodeWriter, func() error, error) {
+	encoder := json.NewEncoder(w)
+
+	write := func(row database.ExportNodesRow) error {
+		return encoder.Encode(exportedNode{
+			IpAddr:   row.IpAddr.String(),
+			Sources:  row.SourceIds,
+			LastSeen: row.LastSeen.Time.Format(time.RFC3339),
+		})
+	}
+
+	return write, w.Flush, nil
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+)
+
+// GetHealth implements api.StrictServerInterface.
+func (s *ServerRoutes) GetHealth(ctx context.Context, request api.GetHealthRequestObject) (api.GetHealthResponseObject, error) {
+	return api.GetHealth200TextResponse("ok"), nil
+}
+
+// GetReadiness implements api.StrictServerInterface.
+func (s *ServerRoutes) GetReadiness(ctx context.Context, request api.GetReadinessRequestObject) (api.GetReadinessResponseObject, error) {
+	status := api.ReadinessStatus{
+		Database:              "ok",
+		ExpectedSchemaVersion: database.SchemaVersion,
+	}
+
+	version, dirty, err := s.queries.GetSchemaVersion(ctx)
+	if err != nil {
+		status.Database = err.Error()
+		return api.GetReadiness503JSONResponse(status), nil
+	}
+
+	status.SchemaVersion = Ptr(int(version))
+	status.SchemaDirty = Ptr(dirty)
+
+	// Newer migrations are fine, they only ever add to the schema this
+	// server was built against.
+	status.Ready = !dirty && version >= database.SchemaVersion
+	if !status.Ready {
+		return api.GetReadiness503JSONResponse(status), nil
+	}
+
+	return api.GetReadiness200JSONResponse(status), nil
+}
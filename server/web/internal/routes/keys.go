@@ -0,0 +1,222 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+	"github.com/jhamill34/prophet-security-takehome/server/web/internal/auth"
+)
+
+var (
+	ErrApiKeyNotFound = errors.New("API key not found")
+)
+
+func apiKeyEntryFromModel(key database.ApiKey) api.ApiKeyEntry {
+	scopes := make([]api.ApiKeyScope, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = api.ApiKeyScope(scope)
+	}
+
+	return api.ApiKeyEntry{
+		Id:         int(key.ID),
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		CreatedAt:  key.CreatedAt.Time.Format(time.RFC3339),
+		LastUsedAt: OptionalTimestamp(key.LastUsedAt),
+		RevokedAt:  OptionalTimestamp(key.RevokedAt),
+	}
+}
+
+func createdApiKeyEntry(key database.ApiKey, plaintext string) api.CreatedApiKeyEntry {
+	entry := apiKeyEntryFromModel(key)
+
+	return api.CreatedApiKeyEntry{
+		Id:         entry.Id,
+		Name:       entry.Name,
+		Prefix:     entry.Prefix,
+		Scopes:     entry.Scopes,
+		CreatedAt:  entry.CreatedAt,
+		LastUsedAt: entry.LastUsedAt,
+		RevokedAt:  entry.RevokedAt,
+		Key:        plaintext,
+	}
+}
+
+// ListApiKeys implements api.StrictServerInterface.
+func (s *ServerRoutes) ListApiKeys(ctx context.Context, request api.ListApiKeysRequestObject) (api.ListApiKeysResponseObject, error) {
+	after, err := strconv.ParseInt(DefaultValue(request.Params.After, "-1"), 10, 32)
+	if err != nil {
+		return api.ListApiKeys400TextResponse(err.Error()), nil
+	}
+
+	limit := DefaultValue(request.Params.Limit, 10)
+	if limit < 1 {
+		return api.ListApiKeys400TextResponse(ErrInvalidLimit.Error()), nil
+	}
+
+	dbResult, err := s.queries.ListApiKeys(ctx, database.ListApiKeysParams{
+		ID:    int32(after),
+		Limit: int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbResult, hasMore := TrimPage(dbResult, limit)
+
+	result := make([]api.ApiKeyEntry, len(dbResult))
+	for i, r := range dbResult {
+		result[i] = apiKeyEntryFromModel(r)
+	}
+
+	cursor := ""
+	if len(result) > 0 {
+		cursor = fmt.Sprintf("%d", result[len(result)-1].Id)
+	}
+
+	response := api.PaginatedApiKeyEntry{
+		Total:   len(result),
+		Cursor:  cursor,
+		HasMore: hasMore,
+		Data:    result,
+	}
+
+	return api.ListApiKeys200JSONResponse(response), nil
+}
+
+// CreateApiKey implements api.StrictServerInterface.
+func (s *ServerRoutes) CreateApiKey(ctx context.Context, request api.CreateApiKeyRequestObject) (api.CreateApiKeyResponseObject, error) {
+	scopes := make([]string, len(request.Body.Scopes))
+	for i, scope := range request.Body.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	plaintext, prefix, err := auth.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var dbResult database.ApiKey
+	err = s.withTx(ctx, func(queries *database.Queries) error {
+		dbResult, err = queries.CreateApiKey(ctx, database.CreateApiKeyParams{
+			Name:    request.Body.Name,
+			Prefix:  prefix,
+			KeyHash: auth.HashKey(plaintext),
+			Scopes:  scopes,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "api_key.create",
+			resourceType: api.AuditResourceTypeApiKey,
+			resourceId:   int(dbResult.ID),
+			after:        apiKeyEntryFromModel(dbResult),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return api.CreateApiKey201JSONResponse(createdApiKeyEntry(dbResult, plaintext)), nil
+}
+
+// RevokeApiKey implements api.StrictServerInterface.
+func (s *ServerRoutes) RevokeApiKey(ctx context.Context, request api.RevokeApiKeyRequestObject) (api.RevokeApiKeyResponseObject, error) {
+	before, err := s.queries.GetApiKey(ctx, int32(request.Id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return api.RevokeApiKey404TextResponse(ErrApiKeyNotFound.Error()), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withTx(ctx, func(queries *database.Queries) error {
+		after, err := queries.RevokeApiKey(ctx, before.ID)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "api_key.revoke",
+			resourceType: api.AuditResourceTypeApiKey,
+			resourceId:   int(before.ID),
+			before:       apiKeyEntryFromModel(before),
+			after:        apiKeyEntryFromModel(after),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return api.RevokeApiKey204Response{}, nil
+}
+
+// RotateApiKey implements api.StrictServerInterface.
+func (s *ServerRoutes) RotateApiKey(ctx context.Context, request api.RotateApiKeyRequestObject) (api.RotateApiKeyResponseObject, error) {
+	plaintext, prefix, err := auth.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// Revoking the old key and inserting the replacement happen in a single
+	// statement, a key that is already revoked can not be rotated. They are
+	// audited as the revoke of the old key and the rotate creating the new one.
+	var dbResult database.ApiKey
+	err = s.withTx(ctx, func(queries *database.Queries) error {
+		before, err := queries.GetApiKey(ctx, int32(request.Id))
+		if err != nil {
+			return err
+		}
+
+		dbResult, err = queries.RotateApiKey(ctx, database.RotateApiKeyParams{
+			ID:      before.ID,
+			Prefix:  prefix,
+			KeyHash: auth.HashKey(plaintext),
+		})
+		if err != nil {
+			return err
+		}
+
+		revoked, err := queries.GetApiKey(ctx, before.ID)
+		if err != nil {
+			return err
+		}
+
+		err = recordAudit(ctx, queries, auditRecord{
+			operation:    "api_key.revoke",
+			resourceType: api.AuditResourceTypeApiKey,
+			resourceId:   int(before.ID),
+			before:       apiKeyEntryFromModel(before),
+			after:        apiKeyEntryFromModel(revoked),
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "api_key.rotate",
+			resourceType: api.AuditResourceTypeApiKey,
+			resourceId:   int(dbResult.ID),
+			after:        apiKeyEntryFromModel(dbResult),
+		})
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return api.RotateApiKey404TextResponse(ErrApiKeyNotFound.Error()), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.RotateApiKey201JSONResponse(createdApiKeyEntry(dbResult, plaintext)), nil
+}
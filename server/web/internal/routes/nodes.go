@@ -2,122 +2,305 @@ package routes
 
 import (
 	"context"
-	"slices"
-	"strings"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
 	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
 )
 
+var (
+	ErrNodeNotFound = errors.New("Node not found")
+	ErrInvalidLimit = errors.New("limit must be at least 1")
+)
+
 // ListAggregatedNodes implements api.StrictServerInterface.
 func (s *ServerRoutes) ListAggregatedNodes(ctx context.Context, request api.ListAggregatedNodesRequestObject) (api.ListAggregatedNodesResponseObject, error) {
-	resultMap := make(map[string]*api.NodeEntry, 0)
-	invert := DefaultValue(request.Params.Invert, false)
 	limit := DefaultValue(request.Params.Limit, 10)
+	if limit < 1 {
+		return api.ListAggregatedNodes400TextResponse(ErrInvalidLimit.Error()), nil
+	}
+
 	after, err := ParseIp(request.Params.After)
 	if err != nil {
 		return api.ListAggregatedNodes400TextResponse(err.Error()), nil
 	}
 
-	if request.Params.AllowlistId == nil {
-		dbResult, err := s.queries.ListAllNodes(ctx, database.ListAllNodesParams{
-			IpAddr: after,
-			Limit:  int32(limit),
-		})
-		if err != nil {
-			return nil, err
-		}
+	response, err := s.listAggregatedNodes(ctx, nodeFilter{
+		allowlistId: request.Params.AllowlistId,
+		invert:      DefaultValue(request.Params.Invert, false),
+	}, after, limit, DefaultValue(request.Params.Count, false))
+	if err != nil {
+		return nil, err
+	}
 
-		for _, r := range dbResult {
-			sourceEntry := api.NodeSourceEntry{
-				SourceId:      int(r.SourceID),
-				Version:       int(r.Version.Int64),
-				LastExecution: r.LastExecution.Time.Format(time.RFC3339),
-			}
-			if entry, ok := resultMap[r.IpAddr.String()]; ok {
-				entry.Sources = append(entry.Sources, sourceEntry)
-			} else {
-				resultMap[r.IpAddr.String()] = &api.NodeEntry{
-					IpAddr:  r.IpAddr.String(),
-					Sources: []api.NodeSourceEntry{sourceEntry},
-				}
-			}
-		}
-	} else {
-		if invert {
-			dbResult, err := s.queries.ListNodesWithoutAllowlist(ctx, database.ListNodesWithoutAllowlistParams{
-				IpAddr: after,
-				Limit:  int32(limit),
-				ListID: int32(*request.Params.AllowlistId),
-			})
-			if err != nil {
-				return nil, err
-			}
-
-			for _, r := range dbResult {
-				sourceEntry := api.NodeSourceEntry{
-					SourceId:      int(r.SourceID),
-					Version:       int(r.Version.Int64),
-					LastExecution: r.LastExecution.Time.Format(time.RFC3339),
-				}
-				if entry, ok := resultMap[r.IpAddr.String()]; ok {
-					entry.Sources = append(entry.Sources, sourceEntry)
-				} else {
-					resultMap[r.IpAddr.String()] = &api.NodeEntry{
-						IpAddr:  r.IpAddr.String(),
-						Sources: []api.NodeSourceEntry{sourceEntry},
-					}
-				}
-			}
-		} else {
-			dbResult, err := s.queries.ListFilteredAllowlistNodes(ctx, database.ListFilteredAllowlistNodesParams{
-				IpAddr: after,
-				Limit:  int32(limit),
-				ListID: int32(*request.Params.AllowlistId),
-			})
-			if err != nil {
-				return nil, err
-			}
-
-			for _, r := range dbResult {
-				sourceEntry := api.NodeSourceEntry{
-					SourceId:      int(r.SourceID),
-					Version:       int(r.Version.Int64),
-					LastExecution: r.LastExecution.Time.Format(time.RFC3339),
-				}
-				if entry, ok := resultMap[r.IpAddr.String()]; ok {
-					entry.Sources = append(entry.Sources, sourceEntry)
-				} else {
-					resultMap[r.IpAddr.String()] = &api.NodeEntry{
-						IpAddr:  r.IpAddr.String(),
-						Sources: []api.NodeSourceEntry{sourceEntry},
-					}
-				}
-			}
-		}
+	return api.ListAggregatedNodes200JSONResponse(response), nil
+}
+
+type nodeFilter struct {
+	sourceId    *int
+	allowlistId *int
+	invert      bool
+}
 
+func (f nodeFilter) params() (pgtype.Int4, pgtype.Int4) {
+	var sourceId, listId pgtype.Int4
+	if f.sourceId != nil {
+		sourceId.Scan(int64(*f.sourceId))
 	}
 
-	result := make([]api.NodeEntry, 0, len(resultMap))
-	for _, v := range resultMap {
-		result = append(result, *v)
+	if f.allowlistId != nil {
+		listId.Scan(int64(*f.allowlistId))
 	}
 
-	slices.SortFunc(result, func(a, b api.NodeEntry) int {
-		return strings.Compare(a.IpAddr, b.IpAddr)
+	return sourceId, listId
+}
+
+// listAggregatedNodes returns a page of nodes grouped by ip address. One extra
+// row is fetched to find out if there is another page.
+func (s *ServerRoutes) listAggregatedNodes(ctx context.Context, filter nodeFilter, after netip.Addr, limit int, count bool) (api.PaginatedNodeEntry, error) {
+	sourceId, listId := filter.params()
+
+	dbResult, err := s.queries.ListAggregatedNodes(ctx, database.ListAggregatedNodesParams{
+		After:      after,
+		SourceID:   sourceId,
+		ListID:     listId,
+		Invert:     filter.invert,
+		MaxResults: int32(limit + 1),
 	})
+	if err != nil {
+		return api.PaginatedNodeEntry{}, err
+	}
+
+	dbResult, hasMore := TrimPage(dbResult, limit)
+
+	result := make([]api.NodeEntry, len(dbResult))
+	for i, r := range dbResult {
+		result[i] = nodeEntryFromAggregate(r)
+	}
 
 	paginatedMetadata := MakePaginated(result, limit, func(item api.NodeEntry) string {
 		return item.IpAddr
 	})
 
-	response := api.ListAggregatedNodes200JSONResponse{
+	response := api.PaginatedNodeEntry{
 		Cursor:  paginatedMetadata.Cursor,
-		HasMore: paginatedMetadata.HasMore,
+		HasMore: hasMore,
 		Total:   paginatedMetadata.Total,
 		Data:    result,
 	}
 
+	if count {
+		totalCount, err := s.queries.CountAggregatedNodes(ctx, database.CountAggregatedNodesParams{
+			SourceID: sourceId,
+			ListID:   listId,
+			Invert:   filter.invert,
+		})
+		if err != nil {
+			return api.PaginatedNodeEntry{}, err
+		}
+
+		response.TotalCount = Ptr(int(totalCount))
+	}
+
+	return response, nil
+}
+
+func nodeEntryFromAggregate(r database.ListAggregatedNodesRow) api.NodeEntry {
+	firstSeen := r.FirstSeen
+	if !firstSeen.Valid {
+		firstSeen = r.LastSeen
+	}
+
+	sources := make([]api.NodeSourceEntry, len(r.SourceIds))
+	for i, sourceId := range r.SourceIds {
+		sources[i] = api.NodeSourceEntry{
+			SourceId:      int(sourceId),
+			Version:       int(r.Versions[i]),
+			LastExecution: r.LastExecutions[i].Time.Format(time.RFC3339),
+		}
+	}
+
+	return api.NodeEntry{
+		IpAddr:    r.IpAddr.String(),
+		FirstSeen: firstSeen.Time.Format(time.RFC3339),
+		LastSeen:  r.LastSeen.Time.Format(time.RFC3339),
+		Sources:   sources,
+	}
+}
+
+// groupNodeRow folds a per source node row into the entry for its ip address.
+// first_seen is the same for every row of an ip, last_seen is the latest of
+// its sources.
+func groupNodeRow(
+	resultMap map[string]*api.NodeEntry,
+	ipAddr netip.Addr,
+	sourceId int32,
+	version pgtype.Int8,
+	lastExecution pgtype.Timestamp,
+	firstSeen pgtype.Timestamp,
+	lastSeen pgtype.Timestamp,
+) {
+	sourceEntry := api.NodeSourceEntry{
+		SourceId:      int(sourceId),
+		Version:       int(version.Int64),
+		LastExecution: lastExecution.Time.Format(time.RFC3339),
+	}
+
+	entry, ok := resultMap[ipAddr.String()]
+	if !ok {
+		if !firstSeen.Valid {
+			firstSeen = lastSeen
+		}
+
+		resultMap[ipAddr.String()] = &api.NodeEntry{
+			IpAddr:    ipAddr.String(),
+			FirstSeen: firstSeen.Time.Format(time.RFC3339),
+			LastSeen:  lastSeen.Time.Format(time.RFC3339),
+			Sources:   []api.NodeSourceEntry{sourceEntry},
+		}
+		return
+	}
+
+	entry.Sources = append(entry.Sources, sourceEntry)
+	if formatted := lastSeen.Time.Format(time.RFC3339); formatted > entry.LastSeen {
+		entry.LastSeen = formatted
+	}
+}
+
+// ListNodeHistory implements api.StrictServerInterface.
+func (s *ServerRoutes) ListNodeHistory(ctx context.Context, request api.ListNodeHistoryRequestObject) (api.ListNodeHistoryResponseObject, error) {
+	ipAddr, err := netip.ParseAddr(request.Ip)
+	if err != nil {
+		return api.ListNodeHistory400TextResponse(err.Error()), nil
+	}
+
+	limit := DefaultValue(request.Params.Limit, 10)
+	if limit < 1 {
+		return api.ListNodeHistory400TextResponse(ErrInvalidLimit.Error()), nil
+	}
+
+	after, err := strconv.ParseInt(DefaultValue(request.Params.After, "0"), 10, 64)
+	if err != nil {
+		return api.ListNodeHistory400TextResponse(err.Error()), nil
+	}
+
+	var sourceId pgtype.Int4
+	if request.Params.SourceId != nil {
+		sourceId.Scan(int64(*request.Params.SourceId))
+	}
+
+	dbResult, err := s.queries.ListNodeEvents(ctx, database.ListNodeEventsParams{
+		IpAddr:   ipAddr,
+		ID:       after,
+		Limit:    int32(limit + 1),
+		SourceID: sourceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbResult, hasMore := TrimPage(dbResult, limit)
+
+	result := make([]api.NodeHistoryEntry, len(dbResult))
+	for i, r := range dbResult {
+		result[i] = api.NodeHistoryEntry{
+			Id:         int(r.ID),
+			IpAddr:     r.IpAddr.String(),
+			SourceId:   int(r.SourceID),
+			Event:      api.NodeHistoryEntryEvent(r.Event),
+			OccurredAt: r.OccurredAt.Time.Format(time.RFC3339),
+		}
+	}
+
+	cursor := ""
+	if len(result) > 0 {
+		cursor = fmt.Sprintf("%d", result[len(result)-1].Id)
+	}
+
+	response := api.ListNodeHistory200JSONResponse{
+		Cursor:  cursor,
+		HasMore: hasMore,
+		Total:   len(result),
+		Data:    result,
+	}
+
 	return response, nil
 }
+
+func (s *ServerRoutes) lookupNodes(ctx context.Context, ipAddrs []netip.Addr, allowlistId *int, invert *bool) (map[string]*api.NodeEntry, error) {
+	var listId pgtype.Int4
+	if allowlistId != nil {
+		listId.Scan(int64(*allowlistId))
+	}
+
+	dbResult, err := s.queries.LookupNodes(ctx, database.LookupNodesParams{
+		IpAddrs: ipAddrs,
+		ListID:  listId,
+		Invert:  DefaultValue(invert, false),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resultMap := make(map[string]*api.NodeEntry)
+	for _, r := range dbResult {
+		groupNodeRow(resultMap, r.IpAddr, r.SourceID, r.Version, r.LastExecution, r.FirstSeen, r.LastSeen)
+	}
+
+	return resultMap, nil
+}
+
+// GetNode implements api.StrictServerInterface.
+func (s *ServerRoutes) GetNode(ctx context.Context, request api.GetNodeRequestObject) (api.GetNodeResponseObject, error) {
+	ipAddr, err := netip.ParseAddr(request.Ip)
+	if err != nil {
+		return api.GetNode400TextResponse(err.Error()), nil
+	}
+
+	resultMap, err := s.lookupNodes(ctx, []netip.Addr{ipAddr}, request.Params.AllowlistId, request.Params.Invert)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := resultMap[ipAddr.String()]
+	if !ok {
+		return api.GetNode404TextResponse(ErrNodeNotFound.Error()), nil
+	}
+
+	return api.GetNode200JSONResponse(*entry), nil
+}
+
+// LookupNodes implements api.StrictServerInterface.
+func (s *ServerRoutes) LookupNodes(ctx context.Context, request api.LookupNodesRequestObject) (api.LookupNodesResponseObject, error) {
+	ipAddrs := make([]netip.Addr, len(request.Body.IpAddrs))
+	for i, ip := range request.Body.IpAddrs {
+		ipAddr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return api.LookupNodes400TextResponse(err.Error()), nil
+		}
+
+		ipAddrs[i] = ipAddr
+	}
+
+	resultMap, err := s.lookupNodes(ctx, ipAddrs, request.Params.AllowlistId, request.Params.Invert)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]api.NodeLookupResult, len(ipAddrs))
+	for i, ipAddr := range ipAddrs {
+		entry, ok := resultMap[ipAddr.String()]
+		result[i] = api.NodeLookupResult{
+			IpAddr: request.Body.IpAddrs[i],
+			Listed: ok,
+			Node:   entry,
+		}
+	}
+
+	return api.LookupNodes200JSONResponse(result), nil
+}
@@ -1,18 +1,51 @@
 package routes
 
 import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
 	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
 	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/secrets"
 )
 
+// Database is a connection that queries can run on and that can start transactions.
+type Database interface {
+	database.DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 type ServerRoutes struct {
+	db      Database
 	queries *database.Queries
+
+	// secrets is nil when no key is configured.
+	secrets *secrets.Box
 }
 
-func NewServerRoutes(queries *database.Queries) *ServerRoutes {
+func NewServerRoutes(db Database, secrets *secrets.Box) *ServerRoutes {
 	return &ServerRoutes{
-		queries,
+		db,
+		database.New(db),
+		secrets,
+	}
+}
+
+// withTx runs fn with queries bound to a transaction that is committed when fn
+// returns without an error and rolled back otherwise.
+func (s *ServerRoutes) withTx(ctx context.Context, fn func(queries *database.Queries) error) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback(ctx)
+
+	err = fn(s.queries.WithTx(tx))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 var _ api.StrictServerInterface = (*ServerRoutes)(nil)
@@ -0,0 +1,158 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
+	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+)
+
+var (
+	ErrIngestionRunNotFound = errors.New("Ingestion run not found")
+	ErrSourceStopped        = errors.New("Source is stopped, start it before requesting a sync")
+)
+
+func ingestionRunEntryFromModel(run database.IngestionRun) api.IngestionRunEntry {
+	return api.IngestionRunEntry{
+		Id:           run.ID,
+		SourceId:     int(run.SourceID),
+		Trigger:      api.IngestionRunTrigger(run.Trigger),
+		Status:       api.IngestionRunStatus(run.Status),
+		Error:        OptionalText(run.Error),
+		RequestedAt:  run.RequestedAt.Time.Format(time.RFC3339),
+		StartedAt:    OptionalTimestamp(run.StartedAt),
+		FinishedAt:   OptionalTimestamp(run.FinishedAt),
+		Version:      OptionalInt8(run.Version),
+		HttpStatus:   OptionalInt4(run.HttpStatus),
+		BytesFetched: OptionalInt8(run.BytesFetched),
+		RowsParsed:   OptionalInt4(run.RowsParsed),
+		RowsRejected: OptionalInt4(run.RowsRejected),
+		NodesAdded:   OptionalInt4(run.NodesAdded),
+		NodesRemoved: OptionalInt4(run.NodesRemoved),
+	}
+}
+
+// SyncSource implements api.StrictServerInterface.
+func (s *ServerRoutes) SyncSource(ctx context.Context, request api.SyncSourceRequestObject) (api.SyncSourceResponseObject, error) {
+	source, err := s.getSource(ctx, int32(request.Id))
+	if errors.Is(err, ErrSourceNotFound) {
+		return api.SyncSource404TextResponse(err.Error()), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !source.Running {
+		return api.SyncSource409TextResponse(ErrSourceStopped.Error()), nil
+	}
+
+	var result api.IngestionRunEntry
+	err = s.withTx(ctx, func(queries *database.Queries) error {
+		// A run that has not been picked up yet already covers this request.
+		dbResult, err := queries.GetPendingIngestionRun(ctx, int32(source.Id))
+		if err == nil {
+			result = ingestionRunEntryFromModel(dbResult)
+			return nil
+		}
+
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		dbResult, err = queries.CreateIngestionRun(ctx, int32(source.Id))
+		if err != nil {
+			return err
+		}
+
+		result = ingestionRunEntryFromModel(dbResult)
+
+		err = queries.NotifySourceChanged(ctx, int32(source.Id))
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "source.sync",
+			resourceType: api.AuditResourceTypeSource,
+			resourceId:   source.Id,
+			after:        result,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return api.SyncSource202JSONResponse(result), nil
+}
+
+// ListSourceIngestionRuns implements api.StrictServerInterface.
+func (s *ServerRoutes) ListSourceIngestionRuns(ctx context.Context, request api.ListSourceIngestionRunsRequestObject) (api.ListSourceIngestionRunsResponseObject, error) {
+	source, err := s.getSource(ctx, int32(request.Id))
+	if errors.Is(err, ErrSourceNotFound) {
+		return api.ListSourceIngestionRuns404TextResponse(err.Error()), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	limit := DefaultValue(request.Params.Limit, 10)
+	if limit < 1 {
+		return api.ListSourceIngestionRuns400TextResponse(ErrInvalidLimit.Error()), nil
+	}
+
+	// Runs are listed newest first so the cursor counts down from the end.
+	before, err := strconv.ParseInt(DefaultValue(request.Params.After, strconv.FormatInt(math.MaxInt64, 10)), 10, 64)
+	if err != nil {
+		return api.ListSourceIngestionRuns400TextResponse(err.Error()), nil
+	}
+
+	dbResult, err := s.queries.ListSourceIngestionRuns(ctx, database.ListSourceIngestionRunsParams{
+		SourceID:   int32(source.Id),
+		BeforeID:   before,
+		MaxResults: int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dbResult, hasMore := TrimPage(dbResult, limit)
+
+	result := make([]api.IngestionRunEntry, len(dbResult))
+	for i, r := range dbResult {
+		result[i] = ingestionRunEntryFromModel(r)
+	}
+
+	cursor := ""
+	if len(result) > 0 {
+		cursor = fmt.Sprintf("%d", result[len(result)-1].Id)
+	}
+
+	response := api.PaginatedIngestionRunEntry{
+		Total:   len(result),
+		Cursor:  cursor,
+		HasMore: hasMore,
+		Data:    result,
+	}
+
+	return api.ListSourceIngestionRuns200JSONResponse(response), nil
+}
+
+// GetIngestionRun implements api.StrictServerInterface.
+func (s *ServerRoutes) GetIngestionRun(ctx context.Context, request api.GetIngestionRunRequestObject) (api.GetIngestionRunResponseObject, error) {
+	dbResult, err := s.queries.GetIngestionRun(ctx, request.Id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return api.GetIngestionRun404TextResponse(ErrIngestionRunNotFound.Error()), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetIngestionRun200JSONResponse(ingestionRunEntryFromModel(dbResult)), nil
+}
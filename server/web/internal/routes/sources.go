@@ -1,52 +1,90 @@
 package routes
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"slices"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
 	"github.com/jhamill34/prophet-security-takehome/server/database/pkg/database"
+	"github.com/jhamill34/prophet-security-takehome/server/ingest/pkg/jsonpath"
 )
 
 var (
-	ErrSourceNotFound = errors.New("Source not found")
+	ErrSourceNotFound  = errors.New("Source not found")
+	ErrSourceNameTaken = errors.New("A source with that name already exists")
+	ErrMissingJsonPath = errors.New("format_options.path is required for the json format")
 )
 
 func (s *ServerRoutes) getSource(ctx context.Context, id int32) (api.SourceEntry, error) {
 	dbResult, err := s.queries.GetSource(ctx, id)
 	if errors.Is(err, pgx.ErrNoRows) {
-		return api.SourceEntry{}, ErrAllowlistNotFound
+		return api.SourceEntry{}, ErrSourceNotFound
 	}
 
 	if err != nil {
 		return api.SourceEntry{}, err
 	}
 
-	resultPeriod, err := dbResult.Period.Value()
+	return sourceEntryFromModel(dbResult)
+}
+
+func sourceEntryFromModel(source database.Source) (api.SourceEntry, error) {
+	period, err := source.Period.Value()
 	if err != nil {
 		return api.SourceEntry{}, err
 	}
 
+	var formatOptions api.SourceFormatOptions
+	if len(source.FormatOptions) > 0 {
+		err = json.Unmarshal(source.FormatOptions, &formatOptions)
+		if err != nil {
+			return api.SourceEntry{}, err
+		}
+	}
+
 	result := api.SourceEntry{
-		Id:            int(dbResult.ID),
-		Name:          dbResult.Name,
-		Url:           dbResult.Url,
-		Period:        resultPeriod.(string),
-		LastExecution: dbResult.LastExecution.Time.Format(time.RFC3339),
-		Version:       int(dbResult.Version.Int64),
-		Running:       dbResult.Running.Bool,
+		Id:                  int(source.ID),
+		Name:                source.Name,
+		Url:                 source.Url,
+		Period:              period.(string),
+		Format:              api.SourceFormat(source.Format),
+		FormatOptions:       formatOptions,
+		HasCredentials:      len(source.Credentials) > 0,
+		LastExecution:       source.LastExecution.Time.Format(time.RFC3339),
+		Version:             int(source.Version.Int64),
+		Running:             source.Running.Bool,
+		LastSuccess:         OptionalTimestamp(source.LastSuccess),
+		LastError:           OptionalText(source.LastError),
+		ConsecutiveFailures: int(source.ConsecutiveFailures),
+		PausedUntil:         OptionalTimestamp(source.PausedUntil),
 	}
 
 	return result, nil
 }
 
+func validateFormat(format api.SourceFormat, formatOptions api.SourceFormatOptions) error {
+	if format != api.SourceFormatJson {
+		return nil
+	}
+
+	path := DefaultValue(formatOptions.Path, "")
+	if path == "" {
+		return ErrMissingJsonPath
+	}
+
+	// Compiled the same way as by the ingester so a bad path is rejected here
+	// rather than failing every ingestion.
+	_, err := jsonpath.Compile(path)
+	return err
+}
+
 // CreateSource implements api.StrictServerInterface.
 func (s *ServerRoutes) CreateSource(ctx context.Context, request api.CreateSourceRequestObject) (api.CreateSourceResponseObject, error) {
 	var period pgtype.Interval
@@ -55,92 +93,256 @@ func (s *ServerRoutes) CreateSource(ctx context.Context, request api.CreateSourc
 		return api.CreateSource400TextResponse(err.Error()), nil
 	}
 
-	dbResult, err := s.queries.CreateSource(ctx, database.CreateSourceParams{
-		Name:   request.Body.Name,
-		Url:    request.Body.Url,
-		Period: period,
-	})
+	format := DefaultValue(request.Body.Format, api.SourceFormatCsv)
+	formatOptions := DefaultValue(request.Body.FormatOptions, api.SourceFormatOptions{})
+	if err := validateFormat(format, formatOptions); err != nil {
+		return api.CreateSource400TextResponse(err.Error()), nil
+	}
+
+	encodedOptions, err := json.Marshal(formatOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	resultPeriod, err := dbResult.Period.Value()
+	credentials, err := s.requestCredentials(DefaultValue(request.Body.Credentials, api.SourceCredentials{}))
+	if isCredentialsError(err) {
+		return api.CreateSource400TextResponse(err.Error()), nil
+	}
+
 	if err != nil {
 		return nil, err
 	}
-	result := api.SourceEntry{
-		Id:            int(dbResult.ID),
-		Name:          dbResult.Name,
-		Url:           dbResult.Url,
-		Period:        resultPeriod.(string),
-		LastExecution: dbResult.LastExecution.Time.Format(time.RFC3339),
-		Version:       int(dbResult.Version.Int64),
-		Running:       dbResult.Running.Bool,
+
+	var result api.SourceEntry
+	err = s.withTx(ctx, func(queries *database.Queries) error {
+		dbResult, err := queries.CreateSource(ctx, database.CreateSourceParams{
+			Name:          request.Body.Name,
+			Url:           request.Body.Url,
+			Period:        period,
+			Format:        string(format),
+			FormatOptions: encodedOptions,
+		})
+		if err != nil {
+			return err
+		}
+
+		if !credentials.IsEmpty() {
+			sealed, err := s.sealCredentials(dbResult.ID, credentials)
+			if err != nil {
+				return err
+			}
+
+			dbResult, err = queries.SetSourceCredentials(ctx, database.SetSourceCredentialsParams{
+				ID:          dbResult.ID,
+				Credentials: sealed,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err = sourceEntryFromModel(dbResult)
+		if err != nil {
+			return err
+		}
+
+		// Wakes the ingester, the notification is only sent once the
+		// transaction commits.
+		err = queries.NotifySourceChanged(ctx, int32(result.Id))
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "source.create",
+			resourceType: api.AuditResourceTypeSource,
+			resourceId:   result.Id,
+			after:        result,
+		})
+	})
+	if IsUniqueViolation(err) {
+		return api.CreateSource409TextResponse(ErrSourceNameTaken.Error()), nil
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
 	return api.CreateSource201JSONResponse(result), nil
 }
 
-// ListSourceNodes implements api.StrictServerInterface.
-func (s *ServerRoutes) ListSourceNodes(ctx context.Context, request api.ListSourceNodesRequestObject) (api.ListSourceNodesResponseObject, error) {
-	source, err := s.getSource(ctx, int32(request.Id))
+// UpdateSource implements api.StrictServerInterface.
+func (s *ServerRoutes) UpdateSource(ctx context.Context, request api.UpdateSourceRequestObject) (api.UpdateSourceResponseObject, error) {
+	before, err := s.getSource(ctx, int32(request.Id))
 	if errors.Is(err, ErrSourceNotFound) {
-		return api.ListSourceNodes404TextResponse(err.Error()), nil
+		return api.UpdateSource404TextResponse(err.Error()), nil
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	limit := DefaultValue(request.Params.Limit, 10)
-	after, err := ParseIp(request.Params.After)
-	if err != nil {
-		return api.ListSourceNodes400TextResponse(err.Error()), nil
+	params := database.UpdateSourceParams{
+		ID: int32(before.Id),
+	}
+
+	if request.Body.Name != nil {
+		params.Name = pgtype.Text{String: *request.Body.Name, Valid: true}
+	}
+
+	if request.Body.Url != nil {
+		params.Url = pgtype.Text{String: *request.Body.Url, Valid: true}
+		params.FeedChanged = params.FeedChanged || *request.Body.Url != before.Url
+	}
+
+	if request.Body.Period != nil {
+		err = params.Period.Scan(*request.Body.Period)
+		if err != nil {
+			return api.UpdateSource400TextResponse(err.Error()), nil
+		}
+	}
+
+	format := DefaultValue(request.Body.Format, before.Format)
+	formatOptions := DefaultValue(request.Body.FormatOptions, before.FormatOptions)
+	if err := validateFormat(format, formatOptions); err != nil {
+		return api.UpdateSource400TextResponse(err.Error()), nil
+	}
+
+	if request.Body.Format != nil {
+		params.Format = pgtype.Text{String: string(format), Valid: true}
+		params.FeedChanged = params.FeedChanged || format != before.Format
+	}
+
+	if request.Body.FormatOptions != nil {
+		params.FormatOptions, err = json.Marshal(formatOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		previousOptions, err := json.Marshal(before.FormatOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		params.FeedChanged = params.FeedChanged || !bytes.Equal(params.FormatOptions, previousOptions)
+	}
+
+	// Sealed credentials can not be compared, so any that are given are
+	// treated as a new feed unless there were none before and are none after.
+	if request.Body.Credentials != nil {
+		credentials, err := s.requestCredentials(*request.Body.Credentials)
+		if isCredentialsError(err) {
+			return api.UpdateSource400TextResponse(err.Error()), nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		params.Credentials, err = s.sealCredentials(params.ID, credentials)
+		if err != nil {
+			return nil, err
+		}
+
+		params.ReplaceCredentials = true
+		params.FeedChanged = params.FeedChanged || params.Credentials != nil || before.HasCredentials
 	}
 
-	dbResult, err := s.queries.ListSourcesNodes(ctx, database.ListSourcesNodesParams{
-		IpAddr: after,
-		Limit:  int32(limit),
-		ID:     int32(source.Id),
+	var result api.SourceEntry
+	err = s.withTx(ctx, func(queries *database.Queries) error {
+		dbResult, err := queries.UpdateSource(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		result, err = sourceEntryFromModel(dbResult)
+		if err != nil {
+			return err
+		}
+
+		err = queries.NotifySourceChanged(ctx, int32(result.Id))
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "source.update",
+			resourceType: api.AuditResourceTypeSource,
+			resourceId:   before.Id,
+			before:       before,
+			after:        result,
+		})
 	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return api.UpdateSource404TextResponse(ErrSourceNotFound.Error()), nil
+	}
+
+	if IsUniqueViolation(err) {
+		return api.UpdateSource409TextResponse(ErrSourceNameTaken.Error()), nil
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	resultMap := make(map[string]*api.NodeEntry)
-	for _, r := range dbResult {
-		sourceEntry := api.NodeSourceEntry{
-			SourceId:      int(r.SourceID),
-			Version:       int(r.Version.Int64),
-			LastExecution: r.LastExecution.Time.Format(time.RFC3339),
+	return api.UpdateSource200JSONResponse(result), nil
+}
+
+// DeleteSource implements api.StrictServerInterface.
+func (s *ServerRoutes) DeleteSource(ctx context.Context, request api.DeleteSourceRequestObject) (api.DeleteSourceResponseObject, error) {
+	err := s.withTx(ctx, func(queries *database.Queries) error {
+		dbResult, err := queries.DeleteSource(ctx, int32(request.Id))
+		if err != nil {
+			return err
 		}
-		if entry, ok := resultMap[r.IpAddr.String()]; ok {
-			entry.Sources = append(entry.Sources, sourceEntry)
-		} else {
-			resultMap[r.IpAddr.String()] = &api.NodeEntry{
-				IpAddr:  r.IpAddr.String(),
-				Sources: []api.NodeSourceEntry{sourceEntry},
-			}
+
+		before, err := sourceEntryFromModel(dbResult)
+		if err != nil {
+			return err
 		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "source.delete",
+			resourceType: api.AuditResourceTypeSource,
+			resourceId:   before.Id,
+			before:       before,
+		})
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return api.DeleteSource404TextResponse(ErrSourceNotFound.Error()), nil
 	}
 
-	result := make([]api.NodeEntry, 0, len(resultMap))
-	for _, v := range resultMap {
-		result = append(result, *v)
+	if err != nil {
+		return nil, err
 	}
 
-	slices.SortFunc(result, func(a, b api.NodeEntry) int {
-		return strings.Compare(a.IpAddr, b.IpAddr)
-	})
+	return api.DeleteSource204Response{}, nil
+}
 
-	paginatedMetadata := MakePaginated(result, limit, func(entry api.NodeEntry) string {
-		return entry.IpAddr
-	})
+// ListSourceNodes implements api.StrictServerInterface.
+func (s *ServerRoutes) ListSourceNodes(ctx context.Context, request api.ListSourceNodesRequestObject) (api.ListSourceNodesResponseObject, error) {
+	source, err := s.getSource(ctx, int32(request.Id))
+	if errors.Is(err, ErrSourceNotFound) {
+		return api.ListSourceNodes404TextResponse(err.Error()), nil
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	paginated := api.PaginatedNodeEntry{
-		Total:   paginatedMetadata.Total,
-		HasMore: paginatedMetadata.HasMore,
-		Cursor:  paginatedMetadata.Cursor,
-		Data:    result,
+	limit := DefaultValue(request.Params.Limit, 10)
+	if limit < 1 {
+		return api.ListSourceNodes400TextResponse(ErrInvalidLimit.Error()), nil
+	}
+
+	after, err := ParseIp(request.Params.After)
+	if err != nil {
+		return api.ListSourceNodes400TextResponse(err.Error()), nil
+	}
+
+	paginated, err := s.listAggregatedNodes(ctx, nodeFilter{
+		sourceId: &source.Id,
+	}, after, limit, DefaultValue(request.Params.Count, false))
+	if err != nil {
+		return nil, err
 	}
 
 	return api.ListSourceNodes200JSONResponse(paginated), nil
@@ -164,20 +366,10 @@ func (s *ServerRoutes) ListSources(ctx context.Context, request api.ListSourcesR
 
 	result := make([]api.SourceEntry, len(dbResult))
 	for i, r := range dbResult {
-		period, err := r.Period.Value()
+		result[i], err = sourceEntryFromModel(r)
 		if err != nil {
 			return nil, err
 		}
-
-		result[i] = api.SourceEntry{
-			Id:            int(r.ID),
-			Name:          r.Name,
-			Url:           r.Url,
-			Period:        period.(string),
-			LastExecution: r.LastExecution.Time.Format(time.RFC3339),
-			Version:       int(r.Version.Int64),
-			Running:       r.Running.Bool,
-		}
 	}
 
 	paginatedMetadata := MakePaginated(result, limit, func(entry api.SourceEntry) string {
@@ -204,7 +396,30 @@ func (s *ServerRoutes) StartSource(ctx context.Context, request api.StartSourceR
 		return nil, err
 	}
 
-	_, err = s.queries.StartSource(ctx, int32(source.Id))
+	err = s.withTx(ctx, func(queries *database.Queries) error {
+		dbResult, err := queries.StartSource(ctx, int32(source.Id))
+		if err != nil {
+			return err
+		}
+
+		after, err := sourceEntryFromModel(dbResult)
+		if err != nil {
+			return err
+		}
+
+		err = queries.NotifySourceChanged(ctx, int32(source.Id))
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "source.start",
+			resourceType: api.AuditResourceTypeSource,
+			resourceId:   source.Id,
+			before:       source,
+			after:        after,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -216,13 +431,31 @@ func (s *ServerRoutes) StartSource(ctx context.Context, request api.StartSourceR
 func (s *ServerRoutes) StopSource(ctx context.Context, request api.StopSourceRequestObject) (api.StopSourceResponseObject, error) {
 	source, err := s.getSource(ctx, int32(request.Id))
 	if errors.Is(err, ErrSourceNotFound) {
-		return api.StopSource400TextResponse(err.Error()), nil
+		return api.StopSource404TextResponse(err.Error()), nil
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = s.queries.StopSource(ctx, int32(source.Id))
+	err = s.withTx(ctx, func(queries *database.Queries) error {
+		dbResult, err := queries.StopSource(ctx, int32(source.Id))
+		if err != nil {
+			return err
+		}
+
+		after, err := sourceEntryFromModel(dbResult)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, queries, auditRecord{
+			operation:    "source.stop",
+			resourceType: api.AuditResourceTypeSource,
+			resourceId:   source.Id,
+			before:       source,
+			after:        after,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -1,11 +1,18 @@
 package routes
 
 import (
+	"errors"
 	"net/netip"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jhamill34/prophet-security-takehome/server/api/pkg/api"
 )
 
+// uniqueViolation is the postgres error code raised when a unique index rejects a row.
+const uniqueViolation = "23505"
+
 type CursorExtractor[T any] func(item T) string
 
 func MakePaginated[T any](data []T, limit int, cursorExt CursorExtractor[T]) api.PaginatedMetadata {
@@ -24,6 +31,20 @@ func MakePaginated[T any](data []T, limit int, cursorExt CursorExtractor[T]) api
 	}
 }
 
+// TrimPage cuts a page that was fetched with limit + 1 rows back down to
+// limit, reporting whether the extra row showed there are more results.
+func TrimPage[T any](data []T, limit int) ([]T, bool) {
+	if len(data) > limit {
+		return data[:limit], true
+	}
+
+	return data, false
+}
+
+func Ptr[T any](value T) *T {
+	return &value
+}
+
 func DefaultValue[T any](ptr *T, value T) T {
 	if ptr == nil {
 		return value
@@ -39,3 +60,43 @@ func ParseIp(val *string) (netip.Addr, error) {
 
 	return netip.ParseAddr(*val)
 }
+
+func OptionalTimestamp(ts pgtype.Timestamp) *string {
+	if !ts.Valid {
+		return nil
+	}
+
+	result := ts.Time.Format(time.RFC3339)
+	return &result
+}
+
+func OptionalText(text pgtype.Text) *string {
+	if !text.Valid {
+		return nil
+	}
+
+	return &text.String
+}
+
+func OptionalInt4(value pgtype.Int4) *int {
+	if !value.Valid {
+		return nil
+	}
+
+	return Ptr(int(value.Int32))
+}
+
+func OptionalInt8(value pgtype.Int8) *int64 {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.Int64
+}
+
+// IsUniqueViolation reports whether err was caused by a unique index rejecting
+// an insert or update.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
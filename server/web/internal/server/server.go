@@ -1,16 +1,43 @@
 package server
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"net/http"
+	"time"
 )
 
 func NewHttpServer(
-	port uint16,
+	addr string,
 	handler http.Handler,
 ) *http.Server {
 	return &http.Server{
-		Addr:    fmt.Sprintf("127.0.0.1:%d", port),
+		Addr:    addr,
 		Handler: handler,
 	}
 }
+
+// Serve runs the server until ctx is cancelled, then stops accepting new
+// connections and waits up to timeout for in-flight requests to finish.
+func Serve(ctx context.Context, s *http.Server, timeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- s.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := s.Shutdown(shutdownCtx)
+	if err != nil {
+		return errors.Join(err, s.Close())
+	}
+
+	return nil
+}